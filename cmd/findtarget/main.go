@@ -1,69 +1,194 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/e1l1ya/findtarget/internal/platform"
 	"github.com/e1l1ya/findtarget/internal/runner"
+	"github.com/e1l1ya/findtarget/internal/store"
 	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/levels"
 	"github.com/spf13/pflag"
 )
 
 // parseFlags parses the flags and sets defaults if necessary.
-func parseFlags() (*types.Config, bool) {
-	config := &types.Config{}
-	pflag.StringVarP(&config.Template, "template", "t", "", "Path to the template YAML file")
-	silentFlag := pflag.BoolP("silent", "s", false, "Run the script in silent mode without verbose output")
+func parseFlags() *types.Options {
+	options := &types.Options{}
+	pflag.StringVarP(&options.Template, "template", "t", "", "Path to the template YAML file")
+	pflag.BoolVarP(&options.Silent, "silent", "s", false, "Run the script in silent mode without verbose output")
+	pflag.StringVarP(&options.Output, "output", "o", "", "File to write the results to instead of stdout")
+	pflag.StringVar(&options.Format, "format", "txt", "Output format (txt, json, jsonl, csv, markdown, burp, zap)")
+	pflag.StringVar(&options.OutputTemplate, "output-template", "", "Go text/template rendered for every asset, or the name of a template from outputTemplates")
+	pflag.StringVar(&options.OutputDir, "output-dir", "", "Directory to write one folder per program to (<dir>/<platform>/<program>/)")
+	pflag.DurationVar(&options.Watch, "watch", 0, "Run again every interval (e.g. 30m) and only report new programs and assets")
+	pflag.StringVar(&options.State, "state", "", "Path to the state file recording every run (default: user config directory)")
+	pflag.BoolVar(&options.NoState, "no-state", false, "Do not record the run in the state file (not with --watch)")
+	pflag.BoolVar(&options.Removed, "removed", false, "Also report assets removed from a program in watch mode")
+	pflag.StringSliceVar(&options.Proxy, "proxy", nil, "Proxy URL overriding the template (http, https or socks5, comma separated to rotate)")
+	pflag.IntVarP(&options.Concurrency, "concurrency", "c", 1, "Number of requests in flight across the platforms")
+	pflag.DurationVar(&options.Timeout, "timeout", 0, "Stop a run after this duration (e.g. 10m) and write the partial results")
+	pflag.StringVar(&options.CacheDir, "cache-dir", "", "Directory caching the platform responses between runs")
+	pflag.DurationVar(&options.CacheTTL, "cache-ttl", 0, "Use cached responses younger than this without revalidating them (e.g. 6h)")
 	pflag.Parse()
 
 	// Validate that the --template flag is provided
-	if config.Template == "" {
-		log.Fatal("Error: The --template or -t flag is required. Please provide a path to the template YAML file.")
+	if options.Template == "" {
+		gologger.Fatal().Msg("The --template or -t flag is required. Please provide a path to the template YAML file.")
+	}
+	// Watch mode finds the new assets of a cycle in the state file
+	if options.Watch > 0 && options.NoState {
+		gologger.Fatal().Msg("The --no-state flag cannot be used with --watch, which needs the state file to report only new programs and assets.")
 	}
 
-	return config, *silentFlag
+	return options
 }
 
-// Load configs and decide which platform must scan
-func loadPlatform(config *types.Config) error {
-	// Select target from Bugcrowd
-	if config.FindTarget.BugCrowd != nil {
-		err := platform.Bugcrowd(config)
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
+// runDiff parses the flags of the diff subcommand and compares two runs.
+func runDiff(args []string) {
+	flags := pflag.NewFlagSet("diff", pflag.ExitOnError)
+	format := flags.String("format", "text", "Diff output format (text, json)")
+	output := flags.StringP("output", "o", "", "File to write the diff to instead of stdout")
+	flags.Usage = func() {
+		gologger.Print().Msgf("Usage: findtarget diff [flags] old.json new.json\n\n%s", flags.FlagUsages())
 	}
+	flags.Parse(args)
 
-	if config.FindTarget.HackerOne != nil {
-		err := platform.HackerOne(config)
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	if err := runner.Diff(flags.Arg(0), flags.Arg(1), *format, *output); err != nil {
+		gologger.Fatal().Msgf("%v", err)
 	}
-	return nil
 }
 
-func main() {
+// runHistory parses the flags of the history subcommand and queries the state file.
+func runHistory(args []string) {
+	options := &types.Options{}
+	query := store.Query{}
+	flags := pflag.NewFlagSet("history", pflag.ExitOnError)
+	flags.StringVar(&options.State, "state", "", "Path to the state file (default: user config directory)")
+	flags.StringVar(&query.Program, "program", "", "Only show the assets of this program handle")
+	flags.StringVar(&query.Platform, "platform", "", "Only show the assets of this platform")
+	since := flags.String("since", "", "Only show assets first seen since a date (2006-01-02) or for a period (30d, 12h)")
+	format := flags.String("format", "text", "History output format (text, json)")
+	output := flags.StringP("output", "o", "", "File to write the history to instead of stdout")
+	flags.Parse(args)
 
-	// Parse the flags
-	switches, silentFlag := parseFlags()
+	var err error
+	query.Since, err = runner.ParseSince(*since, time.Now())
+	if err != nil {
+		gologger.Fatal().Msgf("%v", err)
+	}
 
-	// Set the silent flag
-	if !silentFlag {
+	if err := runner.History(options, query, *format, *output); err != nil {
+		gologger.Fatal().Msgf("%v", err)
+	}
+}
+
+// runDaemon parses the flags of the daemon subcommand and runs the templates
+// on their schedules until interrupted.
+func runDaemon(args []string) {
+	options := &types.Options{}
+	flags := pflag.NewFlagSet("daemon", pflag.ExitOnError)
+	flags.StringSliceVarP(&options.Templates, "template", "t", nil, "Template YAML file with a schedule key (repeatable)")
+	flags.BoolVarP(&options.Silent, "silent", "s", false, "Run the daemon in silent mode without verbose output")
+	flags.StringVarP(&options.Output, "output", "o", "", "File to write the new programs and assets to instead of stdout")
+	flags.StringVar(&options.Format, "format", "txt", "Output format (txt, json, jsonl, csv, markdown, burp, zap)")
+	flags.StringVar(&options.OutputTemplate, "output-template", "", "Go text/template rendered for every asset, or the name of a template from outputTemplates")
+	flags.StringVar(&options.OutputDir, "output-dir", "", "Directory to write one folder per program to (<dir>/<platform>/<program>/)")
+	flags.StringVar(&options.State, "state", "", "Path to the state file (default: user config directory)")
+	flags.BoolVar(&options.Removed, "removed", false, "Also report assets removed from a program")
+	flags.StringSliceVar(&options.Proxy, "proxy", nil, "Proxy URL overriding the templates (http, https or socks5, comma separated to rotate)")
+	flags.IntVarP(&options.Concurrency, "concurrency", "c", 1, "Number of requests in flight across the platforms")
+	flags.DurationVar(&options.Timeout, "timeout", 0, "Stop a run after this duration (e.g. 10m) and write the partial results")
+	flags.StringVar(&options.CacheDir, "cache-dir", "", "Directory caching the platform responses between runs")
+	flags.DurationVar(&options.CacheTTL, "cache-ttl", 0, "Use cached responses younger than this without revalidating them (e.g. 6h)")
+	flags.Usage = func() {
+		gologger.Print().Msgf("Usage: findtarget daemon -t a.yaml -t b.yaml [flags]\n\n%s", flags.FlagUsages())
+	}
+	flags.Parse(args)
+	options.Templates = append(options.Templates, flags.Args()...)
+
+	if len(options.Templates) == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	setLogLevel(options.Silent)
+
+	ctx, stop := interruptContext()
+	defer stop()
+	if err := runner.Daemon(ctx, options); err != nil {
+		gologger.Fatal().Msgf("%v", err)
+	}
+}
+
+// interruptContext returns a context cancelled on SIGINT or SIGTERM. After
+// the first signal the default handling is restored, so a second one kills
+// the process without waiting for the partial results.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// setLogLevel hides the warnings and the banner in silent mode.
+func setLogLevel(silent bool) {
+	if silent {
+		gologger.DefaultLogger.SetMaxLevel(levels.LevelError)
+	} else {
+		gologger.DefaultLogger.SetMaxLevel(levels.LevelWarning)
 		runner.ShowBanner()
 	}
+}
 
-	// Load environment variables
+func main() {
+
+	// Run the subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "diff":
+			runDiff(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		case "daemon":
+			runDaemon(os.Args[2:])
+			return
+		}
+	}
+
+	// Parse the flags
+	options := parseFlags()
+
+	// Set the silent flag
+	setLogLevel(options.Silent)
 
 	// Load template
-	config, err := runner.LoadTemplate(switches.Template)
+	config, err := runner.LoadTemplate(options.Template)
 	if err != nil {
-		fmt.Printf("Error loading template: %v\n", err)
-		return
+		gologger.Fatal().Msgf("Error loading template: %v", err)
 	}
+	runner.ApplyOptions(config, options)
 
-	loadPlatform(config)
+	ctx, stop := interruptContext()
+	defer stop()
+	if options.Watch > 0 {
+		err = runner.Watch(ctx, config, options)
+	} else {
+		err = runner.Run(ctx, config, options)
+	}
+	if err != nil {
+		gologger.Fatal().Msgf("%v", err)
+	}
 }
@@ -7,6 +7,7 @@ require (
 	github.com/projectdiscovery/gologger v1.1.48
 	github.com/projectdiscovery/httpx v1.6.10
 	github.com/spf13/pflag v1.0.6
+	go.etcd.io/bbolt v1.3.10
 	golang.org/x/net v0.37.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -140,7 +141,6 @@ require (
 	github.com/zcalusic/sysinfo v1.0.2 // indirect
 	github.com/zmap/rc2 v0.0.0-20190804163417-abaa70531248 // indirect
 	github.com/zmap/zcrypto v0.0.0-20240512203510-0fef58d9a9db // indirect
-	go.etcd.io/bbolt v1.3.10 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.org/x/crypto v0.36.0 // indirect
 	golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842 // indirect
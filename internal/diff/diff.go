@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/e1l1ya/findtarget/internal/output"
+)
+
+// Result lists the differences between two runs.
+type Result struct {
+	AddedPrograms   []string        `json:"added_programs"`
+	RemovedPrograms []string        `json:"removed_programs"`
+	AddedAssets     []output.Record `json:"added_assets"`
+	RemovedAssets   []output.Record `json:"removed_assets"`
+	ChangedAssets   []Change        `json:"changed_assets"`
+}
+
+// Change is an attribute of an asset that differs between two runs.
+type Change struct {
+	Platform   string `json:"platform"`
+	Program    string `json:"program"`
+	AssetType  string `json:"asset_type"`
+	Identifier string `json:"identifier"`
+	Field      string `json:"field"`
+	Old        string `json:"old"`
+	New        string `json:"new"`
+}
+
+// Compare compares the records of an old and a new run.
+func Compare(oldRecords []output.Record, newRecords []output.Record) Result {
+	result := Result{
+		AddedPrograms:   []string{},
+		RemovedPrograms: []string{},
+		AddedAssets:     []output.Record{},
+		RemovedAssets:   []output.Record{},
+		ChangedAssets:   []Change{},
+	}
+
+	oldPrograms, oldAssets := index(oldRecords)
+	newPrograms, newAssets := index(newRecords)
+
+	for _, program := range sortedKeys(newPrograms) {
+		if !oldPrograms[program] {
+			result.AddedPrograms = append(result.AddedPrograms, program)
+		}
+	}
+	for _, program := range sortedKeys(oldPrograms) {
+		if !newPrograms[program] {
+			result.RemovedPrograms = append(result.RemovedPrograms, program)
+		}
+	}
+
+	for _, key := range sortedKeys(newAssets) {
+		newRecord := newAssets[key]
+		oldRecord, ok := oldAssets[key]
+		if !ok {
+			result.AddedAssets = append(result.AddedAssets, newRecord)
+			continue
+		}
+		result.ChangedAssets = append(result.ChangedAssets, compareRecords(oldRecord, newRecord)...)
+	}
+	for _, key := range sortedKeys(oldAssets) {
+		if _, ok := newAssets[key]; !ok {
+			result.RemovedAssets = append(result.RemovedAssets, oldAssets[key])
+		}
+	}
+
+	return result
+}
+
+// compareRecords returns the changed attributes of an asset.
+func compareRecords(oldRecord output.Record, newRecord output.Record) []Change {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"in_scope", strconv.FormatBool(oldRecord.InScope), strconv.FormatBool(newRecord.InScope)},
+		{"bounty", strconv.FormatBool(oldRecord.Bounty), strconv.FormatBool(newRecord.Bounty)},
+		{"max_severity", oldRecord.MaxSeverity, newRecord.MaxSeverity},
+	}
+
+	var changes []Change
+	for _, field := range fields {
+		if field.old == field.new {
+			continue
+		}
+		changes = append(changes, Change{
+			Platform:   newRecord.Platform,
+			Program:    newRecord.Program,
+			AssetType:  newRecord.AssetType,
+			Identifier: newRecord.Identifier,
+			Field:      field.name,
+			Old:        field.old,
+			New:        field.new,
+		})
+	}
+	return changes
+}
+
+// index returns the programs and the assets of a run keyed by identity.
+func index(records []output.Record) (map[string]bool, map[string]output.Record) {
+	programs := make(map[string]bool)
+	assets := make(map[string]output.Record)
+	for _, record := range records {
+		program := record.Platform + "/" + record.Program
+		programs[program] = true
+		assets[program+"\x00"+record.AssetType+"\x00"+record.Identifier] = record
+	}
+	return programs, assets
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WriteText writes the differences in a line based format.
+func WriteText(w io.Writer, result Result) error {
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+
+	for _, program := range result.AddedPrograms {
+		printf("[+] program %s\n", program)
+	}
+	for _, program := range result.RemovedPrograms {
+		printf("[-] program %s\n", program)
+	}
+	for _, record := range result.AddedAssets {
+		printf("[+] %s/%s %s %s\n", record.Platform, record.Program, record.AssetType, record.Identifier)
+	}
+	for _, record := range result.RemovedAssets {
+		printf("[-] %s/%s %s %s\n", record.Platform, record.Program, record.AssetType, record.Identifier)
+	}
+	for _, change := range result.ChangedAssets {
+		printf("[~] %s/%s %s %s %s: %s -> %s\n", change.Platform, change.Program, change.AssetType, change.Identifier, change.Field, change.Old, change.New)
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// originServer serves a versioned body with validators and records the
+// requests it receives.
+type originServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	version  string
+	requests []*http.Request
+}
+
+// lastModified is the Last-Modified of every response of the origin server.
+var lastModified = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+
+func newOriginServer(t *testing.T) *originServer {
+	s := &originServer{version: "v1"}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		version := s.version
+		s.mu.Unlock()
+
+		etag := `"` + version + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		io.WriteString(w, version+" for "+r.Header.Get("Authorization")+r.Header.Get("Cookie"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received returns the requests received so far.
+func (s *originServer) received() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// fetch sends a GET request with the headers and returns the response body.
+func fetch(t *testing.T, client *http.Client, url string, header http.Header) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header = header
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func newCacheClient(t *testing.T, ttl time.Duration) *http.Client {
+	client, err := New(Options{Retries: -1, CacheDir: t.TempDir(), CacheTTL: ttl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestCacheFreshHit(t *testing.T) {
+	server := newOriginServer(t)
+	client := newCacheClient(t, time.Hour)
+
+	first := fetch(t, client, server.URL, nil)
+	server.mu.Lock()
+	server.version = "v2"
+	server.mu.Unlock()
+	if second := fetch(t, client, server.URL, nil); second != first {
+		t.Errorf("cached body = %q, want %q", second, first)
+	}
+	if got := len(server.received()); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestCacheRevalidates(t *testing.T) {
+	server := newOriginServer(t)
+	// Every entry is stale right away
+	client := newCacheClient(t, 0)
+
+	first := fetch(t, client, server.URL, nil)
+	if second := fetch(t, client, server.URL, nil); second != first {
+		t.Errorf("body after 304 = %q, want the cached %q", second, first)
+	}
+
+	requests := server.received()
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want 2", len(requests))
+	}
+	if got := requests[1].Header.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q", got)
+	}
+	if got := requests[1].Header.Get("If-Modified-Since"); got != lastModified {
+		t.Errorf("If-Modified-Since = %q", got)
+	}
+
+	// A changed resource replaces the entry
+	server.mu.Lock()
+	server.version = "v2"
+	server.mu.Unlock()
+	if third := fetch(t, client, server.URL, nil); third != "v2 for " {
+		t.Errorf("body after a change = %q", third)
+	}
+	if got := fetch(t, client, server.URL, nil); got != "v2 for " {
+		t.Errorf("body after a 304 of the new entry = %q", got)
+	}
+}
+
+func TestCacheKeyedByCredentials(t *testing.T) {
+	for _, header := range []string{"Authorization", "Cookie"} {
+		t.Run(header, func(t *testing.T) {
+			server := newOriginServer(t)
+			client := newCacheClient(t, time.Hour)
+
+			alice := http.Header{header: {"alice"}}
+			bob := http.Header{header: {"bob"}}
+			for _, want := range []struct {
+				header http.Header
+				body   string
+			}{
+				{alice, "v1 for alice"},
+				{bob, "v1 for bob"},
+				{nil, "v1 for "},
+				{alice, "v1 for alice"},
+				{bob, "v1 for bob"},
+			} {
+				if got := fetch(t, client, server.URL, want.header); got != want.body {
+					t.Errorf("%s %v: body = %q, want %q", header, want.header.Get(header), got, want.body)
+				}
+			}
+			// Only the first request of every account reaches the server
+			if got := len(server.received()); got != 3 {
+				t.Errorf("requests = %d, want 3", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// DefaultTimeout bounds every request when the template sets no timeout.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultUserAgent is sent when neither the template nor the request sets one.
+const DefaultUserAgent = "findtarget/1.0.0"
+
+// Options configures the clients created by New.
+type Options struct {
+	Proxies []string          // Proxy URLs rotated per request, empty to connect directly
+	Timeout time.Duration     // Timeout of every attempt, DefaultTimeout when zero
+	Headers map[string]string // Headers added to requests that do not set them
+
+	RateLimit float64 // Maximum requests per second, zero for no limit
+	Retries   int     // Retries of a failed request, DefaultRetries when zero, negative for none
+
+	CacheDir string        // Directory of the response cache, empty to disable it
+	CacheTTL time.Duration // Age under which cached responses are used without revalidation
+}
+
+// New creates an HTTP client applying the proxies, timeout, headers, rate
+// limit, retries and response cache of the options. Proxies may be http://,
+// https:// or socks5:// URLs, with optional credentials. A proxy that cannot
+// be honoured is an error, so requests never silently bypass it.
+func New(options Options) (*http.Client, error) {
+	var transport http.RoundTripper
+	switch len(options.Proxies) {
+	case 0:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case 1:
+		proxyTransport, err := newProxyTransport(options.Proxies[0])
+		if err != nil {
+			return nil, err
+		}
+		transport = proxyTransport
+	default:
+		rotation := &rotatingTransport{}
+		for _, proxyURL := range options.Proxies {
+			proxyTransport, err := newProxyTransport(proxyURL)
+			if err != nil {
+				return nil, err
+			}
+			rotation.transports = append(rotation.transports, proxyTransport)
+		}
+		transport = rotation
+	}
+
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	retries := options.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	} else if retries < 0 {
+		retries = 0
+	}
+
+	headers := map[string]string{"User-Agent": DefaultUserAgent}
+	for key, value := range options.Headers {
+		headers[http.CanonicalHeaderKey(key)] = value
+	}
+
+	transport = &retryTransport{base: transport, retries: retries, timeout: timeout, limiter: newLimiter(options.RateLimit)}
+	if options.CacheDir != "" {
+		if err := os.MkdirAll(options.CacheDir, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create cache directory: %v", err)
+		}
+		// Cache hits neither wait for the rate limit nor count as requests
+		transport = &cacheTransport{base: transport, dir: options.CacheDir, ttl: options.CacheTTL}
+	}
+	return &http.Client{Transport: &headerTransport{base: transport, headers: headers}}, nil
+}
+
+// newProxyTransport creates a transport sending every request through a proxy.
+func newProxyTransport(rawURL string) (*http.Transport, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		// The parse error quotes the URL, which may hold credentials
+		return nil, fmt.Errorf("invalid proxy URL: %v", errors.Unwrap(err))
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL.Redacted())
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch proxyURL.Scheme {
+	case "http", "https":
+		// The credentials of the URL are sent as Proxy-Authorization
+		transport.Proxy = http.ProxyURL(proxyURL)
+	case "socks5", "socks5h":
+		dialer, err := socksDialer(proxyURL, proxy.Direct)
+		if err != nil {
+			return nil, err
+		}
+		// The environment proxy settings must not override the template
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	return transport, nil
+}
+
+// Dialer returns a dialer opening TCP connections through a SOCKS5 proxy, or
+// directly when rawURL is empty. HTTP proxies only carry HTTP requests and are
+// an error.
+func Dialer(rawURL string, timeout time.Duration) (proxy.ContextDialer, error) {
+	direct := &net.Dialer{Timeout: timeout}
+	if rawURL == "" {
+		return direct, nil
+	}
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %v", errors.Unwrap(err))
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: missing host", proxyURL.Redacted())
+	}
+	if proxyURL.Scheme != "socks5" && proxyURL.Scheme != "socks5h" {
+		return nil, fmt.Errorf("proxy %q cannot carry non-HTTP connections, use a socks5:// proxy", proxyURL.Redacted())
+	}
+	return socksDialer(proxyURL, direct)
+}
+
+// socksDialer creates a dialer connecting through a SOCKS5 proxy URL.
+func socksDialer(proxyURL *url.URL, forward proxy.Dialer) (proxy.ContextDialer, error) {
+	var auth *proxy.Auth
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+	}
+	dialer, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, forward)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SOCKS5 proxy dialer: %v", err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("SOCKS5 proxy dialer does not support contexts")
+	}
+	return contextDialer, nil
+}
+
+// rotatingTransport sends every request through the next proxy of the list.
+type rotatingTransport struct {
+	transports []*http.Transport
+	next       atomic.Uint64
+}
+
+func (t *rotatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := t.next.Add(1) - 1
+	return t.transports[i%uint64(len(t.transports))].RoundTrip(req)
+}
+
+// headerTransport adds default headers to the requests missing them.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var missing bool
+	for key := range t.headers {
+		if req.Header.Get(key) == "" {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the request it was given
+	req = req.Clone(req.Context())
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+	for key, value := range t.headers {
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,303 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// shortBackoff shortens the backoff for the duration of the test.
+func shortBackoff(t *testing.T, base time.Duration) {
+	previousBase, previousMax := baseBackoff, maxBackoff
+	baseBackoff, maxBackoff = base, 10*base
+	t.Cleanup(func() { baseBackoff, maxBackoff = previousBase, previousMax })
+}
+
+// flakyServer answers the requests with the handlers in order, repeating the
+// last one, and records the bodies it receives.
+type flakyServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   []string
+	handlers []http.HandlerFunc
+}
+
+func newFlakyServer(t *testing.T, handlers ...http.HandlerFunc) *flakyServer {
+	s := &flakyServer{handlers: handlers}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		handler := s.handlers[min(len(s.bodies), len(s.handlers))-1]
+		s.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// attempts returns the number of requests received.
+func (s *flakyServer) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bodies)
+}
+
+// status answers with a status code and optional Retry-After.
+func status(code int, retryAfter string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(code)
+	}
+}
+
+// hangUp closes the connection without a response.
+func hangUp(w http.ResponseWriter, r *http.Request) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err == nil {
+		t.Cleanup(func() { resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func TestRetryStatusAndNetworkErrors(t *testing.T) {
+	shortBackoff(t, time.Millisecond)
+
+	tests := []struct {
+		name     string
+		retries  int
+		handlers []http.HandlerFunc
+		status   int
+		attempts int
+	}{
+		{"too many requests", 0, []http.HandlerFunc{status(http.StatusTooManyRequests, ""), status(http.StatusOK, "")}, http.StatusOK, 2},
+		{"server errors", 0, []http.HandlerFunc{status(http.StatusInternalServerError, ""), status(http.StatusBadGateway, ""), status(http.StatusOK, "")}, http.StatusOK, 3},
+		{"network error", 0, []http.HandlerFunc{hangUp, status(http.StatusOK, "")}, http.StatusOK, 2},
+		{"client error", 0, []http.HandlerFunc{status(http.StatusNotFound, ""), status(http.StatusOK, "")}, http.StatusNotFound, 1},
+		{"exhausted", 2, []http.HandlerFunc{status(http.StatusServiceUnavailable, "")}, http.StatusServiceUnavailable, 3},
+		{"disabled", -1, []http.HandlerFunc{status(http.StatusServiceUnavailable, ""), status(http.StatusOK, "")}, http.StatusServiceUnavailable, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFlakyServer(t, test.handlers...)
+			client, err := New(Options{Retries: test.retries})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := get(t, client, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != test.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, test.status)
+			}
+			if got := server.attempts(); got != test.attempts {
+				t.Errorf("attempts = %d, want %d", got, test.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryDisabledNetworkError(t *testing.T) {
+	server := newFlakyServer(t, hangUp, status(http.StatusOK, ""))
+	client, err := New(Options{Retries: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := get(t, client, server.URL); err == nil {
+		t.Error("expected an error")
+	}
+	if got := server.attempts(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-5", 0, false},
+		{"soon", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Hour).Format(http.TimeFormat), 0, true},
+		{now.Add(time.Hour).Format(time.RFC850), time.Hour, true},
+	}
+
+	for _, test := range tests {
+		got, ok := retryAfter(test.value, now)
+		if got != test.want || ok != test.ok {
+			t.Errorf("retryAfter(%q) = %s, %t, want %s, %t", test.value, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	// The backoff alone would retry right away
+	shortBackoff(t, time.Millisecond)
+
+	for name, value := range map[string]func() string{
+		"seconds": func() string { return "1" },
+		"date":    func() string { return time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := newFlakyServer(t, status(http.StatusTooManyRequests, value()), status(http.StatusOK, ""))
+			client, err := New(Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			started := time.Now()
+			resp, err := get(t, client, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// HTTP dates have a one second resolution
+			if elapsed := time.Since(started); elapsed < 900*time.Millisecond {
+				t.Errorf("retried after %s, before the Retry-After", elapsed)
+			}
+			if resp.StatusCode != http.StatusOK || server.attempts() != 2 {
+				t.Errorf("status = %d after %d attempts", resp.StatusCode, server.attempts())
+			}
+		})
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	for name, value := range map[string]string{
+		"seconds": "3600",
+		"date":    time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := newFlakyServer(t, status(http.StatusTooManyRequests, value), status(http.StatusOK, ""))
+			client, err := New(Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := get(t, client, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != http.StatusTooManyRequests || server.attempts() != 1 {
+				t.Errorf("status = %d after %d attempts, want the 429 right away", resp.StatusCode, server.attempts())
+			}
+		})
+	}
+}
+
+func TestRetryRewindsBody(t *testing.T) {
+	shortBackoff(t, time.Millisecond)
+
+	server := newFlakyServer(t, status(http.StatusInternalServerError, ""), hangUp, status(http.StatusOK, ""))
+	client, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"query":"programs"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(server.bodies) != 3 {
+		t.Fatalf("attempts = %d, want 3", len(server.bodies))
+	}
+	for i, body := range server.bodies {
+		if body != `{"query":"programs"}` {
+			t.Errorf("attempt %d sent %q", i, body)
+		}
+	}
+}
+
+func TestRetryAttemptTimeout(t *testing.T) {
+	shortBackoff(t, time.Millisecond)
+
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+	server := newFlakyServer(t, slow, status(http.StatusOK, ""))
+	client, err := New(Options{Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now()
+	resp, err := get(t, client, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("the slow attempt was not timed out, took %s", elapsed)
+	}
+	if resp.StatusCode != http.StatusOK || server.attempts() != 2 {
+		t.Errorf("status = %d after %d attempts", resp.StatusCode, server.attempts())
+	}
+}
+
+func TestRetryCancelStopsBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		backoff time.Duration
+		handler http.HandlerFunc
+	}{
+		{"backoff", time.Hour, status(http.StatusServiceUnavailable, "")},
+		{"retry after", time.Millisecond, status(http.StatusTooManyRequests, "240")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shortBackoff(t, test.backoff)
+			server := newFlakyServer(t, test.handler)
+			client, err := New(Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			started := time.Now()
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+				t.Fatal("expected an error")
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("err = %v, want the context error", err)
+			}
+			if elapsed := time.Since(started); elapsed > 2*time.Second {
+				t.Errorf("returned after %s", elapsed)
+			}
+			if got := server.attempts(); got != 1 {
+				t.Errorf("attempts = %d, want 1", got)
+			}
+		})
+	}
+}
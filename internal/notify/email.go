@@ -0,0 +1,259 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e1l1ya/findtarget/internal/httpclient"
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"golang.org/x/net/proxy"
+)
+
+// emailHTML renders the HTML part of the digest.
+var emailHTML = template.Must(template.New("digest").Parse(`<html><body>
+<p>{{.Summary}}</p>
+{{range .Sections}}<h2>{{.Heading}}</h2>
+{{range .Programs}}<h3>{{.Platform}}: <a href="{{.URL}}">{{if .Name}}{{.Name}}{{else}}{{.Handle}}{{end}}</a></h3>
+<ul>
+{{range .Assets}}<li><code>{{.Identifier}}</code> ({{.Kind}}){{if not .InScope}} <b>out of scope</b>{{end}}{{if .BountyEligible}} <b>bounty</b>{{end}}{{if .MaxSeverity}}, max severity {{.MaxSeverity}}{{end}}</li>
+{{end}}</ul>
+{{end}}{{end}}</body></html>
+`))
+
+// digestSection is a heading of the digest with the programs listed under it.
+type digestSection struct {
+	Heading  string
+	Programs []types.Program
+}
+
+// email sends the new, changed and removed assets as an HTML and plain text
+// digest. With a batch window the runner queues the changes and only hands
+// over the digest once the window elapsed.
+type email struct {
+	config  *types.EmailSinkConfig
+	window  time.Duration
+	dialer  proxy.ContextDialer
+	timeout time.Duration
+}
+
+// newEmail validates the SMTP settings and parses the batch window. The SMTP
+// connection goes through the first proxy of the template, which must be a
+// SOCKS5 proxy.
+func newEmail(config *types.EmailSinkConfig, template *types.Config) (*email, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("no SMTP host provided")
+	}
+	if config.From == "" || len(config.To) == 0 {
+		return nil, fmt.Errorf("email sender and recipients are required")
+	}
+
+	var proxyURL string
+	if len(template.Proxy) > 0 {
+		proxyURL = template.Proxy[0]
+	}
+	timeout := template.Timeout
+	if timeout == 0 {
+		timeout = httpclient.DefaultTimeout
+	}
+	dialer, err := httpclient.Dialer(proxyURL, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("email: %v", err)
+	}
+
+	e := &email{config: config, dialer: dialer, timeout: timeout}
+	switch config.Batch {
+	case "run":
+	case "daily":
+		e.window = 24 * time.Hour
+	default:
+		window, err := time.ParseDuration(config.Batch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email batch %q", config.Batch)
+		}
+		e.window = window
+	}
+	return e, nil
+}
+
+func (e *email) Name() string {
+	return "email"
+}
+
+// Window returns the batch window, zero when every run sends its own digest.
+func (e *email) Window() time.Duration {
+	return e.window
+}
+
+func (e *email) Notify(changes store.Changes) error {
+	msg, err := e.message(changes, time.Now())
+	if err != nil {
+		return err
+	}
+	return e.send(msg)
+}
+
+// message builds a multipart/alternative message with the digest.
+func (e *email) message(changes store.Changes, now time.Time) ([]byte, error) {
+	var sections []digestSection
+	var counts []string
+	programs := make(map[string]bool)
+	for _, section := range []struct {
+		heading, label string
+		programs       []types.Program
+	}{
+		{"New targets", "new", changes.Added},
+		{"Changed targets", "changed", changes.Changed},
+		{"Removed targets", "removed", changes.Removed},
+	} {
+		if len(section.programs) == 0 {
+			continue
+		}
+		assets := 0
+		for _, program := range section.programs {
+			assets += len(program.Assets)
+			programs[program.Platform+"/"+program.Handle] = true
+		}
+		sections = append(sections, digestSection{Heading: section.heading, Programs: section.programs})
+		counts = append(counts, fmt.Sprintf("%d %s", assets, section.label))
+	}
+	summary := fmt.Sprintf("%s targets on %d programs", strings.Join(counts, ", "), len(programs))
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	var text strings.Builder
+	text.WriteString(summary + "\n")
+	for _, section := range sections {
+		for _, program := range section.Programs {
+			text.WriteString("\n" + listing(section.Heading, program))
+		}
+	}
+	if err := writePart(parts, "text/plain", text.String()); err != nil {
+		return nil, err
+	}
+
+	var html strings.Builder
+	err := emailHTML.Execute(&html, struct {
+		Summary  string
+		Sections []digestSection
+	}{summary, sections})
+	if err != nil {
+		return nil, err
+	}
+	if err := writePart(parts, "text/html", html.String()); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", encodeHeader(e.config.Subject+": "+summary))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// writePart adds a quoted-printable UTF-8 part to the message.
+func writePart(parts *multipart.Writer, contentType string, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=UTF-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := parts.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// encodeHeader encodes a header value that is not plain ASCII.
+func encodeHeader(value string) string {
+	for _, r := range value {
+		if r > 127 {
+			return mime.QEncoding.Encode("UTF-8", value)
+		}
+	}
+	return value
+}
+
+// send delivers the message. Without implicit TLS, STARTTLS is used when the
+// server offers it. The whole SMTP session must finish within the timeout.
+func (e *email) send(message []byte) error {
+	addr := net.JoinHostPort(e.config.Host, strconv.Itoa(e.config.Port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	conn, err := e.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(e.timeout)); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	tlsConfig := &tls.Config{ServerName: e.config.Host}
+	if e.config.TLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, e.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to connect to SMTP server: %v", err)
+	}
+	defer client.Close()
+
+	if !e.config.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("failed to start TLS: %v", err)
+			}
+		}
+	}
+	if e.config.Username != "" {
+		auth := smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %v", err)
+		}
+	}
+	if err := client.Mail(e.config.From); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	for _, to := range e.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("failed to send email: %v", err)
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return client.Quit()
+}
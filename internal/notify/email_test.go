@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// smtpSession is what the test SMTP server received.
+type smtpSession struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// serveSMTP accepts one connection on listener and answers a minimal SMTP
+// session without STARTTLS or authentication.
+func serveSMTP(t *testing.T, listener net.Listener) <-chan smtpSession {
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		defer close(sessions)
+		conn, err := listener.Accept()
+		if err != nil {
+			t.Errorf("accept: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var session smtpSession
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Errorf("read: %v", err)
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				session.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				session.recipients = append(session.recipients, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						t.Errorf("read data: %v", err)
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(line, "."))
+				}
+				session.data = data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				sessions <- session
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+	return sessions
+}
+
+func TestEmailNotify(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	sessions := serveSMTP(t, listener)
+
+	config := &types.EmailSinkConfig{
+		Host:    "127.0.0.1",
+		Port:    listener.Addr().(*net.TCPAddr).Port,
+		From:    "findtarget@example.com",
+		To:      []string{"alice@example.com", "bob@example.com"},
+		Subject: "findtarget",
+	}
+	config.SetDefaults()
+	sink, err := newEmail(config, &types.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := store.Changes{
+		Added: []types.Program{{
+			Platform: "hackerone",
+			Handle:   "acme",
+			URL:      "https://hackerone.com/acme",
+			Assets:   []types.Asset{{Kind: types.AssetWildcard, Identifier: "*.acme.com", InScope: true, BountyEligible: true}},
+		}},
+		Changed: []types.Program{{
+			Platform: "bugcrowd",
+			Handle:   "initech",
+			URL:      "https://bugcrowd.com/initech",
+			Assets:   []types.Asset{{Kind: types.AssetURL, Identifier: "api.initech.com", InScope: false}},
+		}},
+	}
+	if err := sink.Notify(changes); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	session, ok := <-sessions
+	if !ok {
+		t.Fatal("no SMTP session completed")
+	}
+	if session.from != config.From {
+		t.Errorf("sender = %q, want %q", session.from, config.From)
+	}
+	if strings.Join(session.recipients, ",") != "alice@example.com,bob@example.com" {
+		t.Errorf("recipients = %v", session.recipients)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(session.data))
+	if err != nil {
+		t.Fatalf("invalid message: %v", err)
+	}
+	if subject := msg.Header.Get("Subject"); subject != "findtarget: 1 new, 1 changed targets on 2 programs" {
+		t.Errorf("subject = %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v)", msg.Header.Get("Content-Type"), err)
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	contents := make(map[string]string)
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		// NextPart decodes quoted-printable parts
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		contents[partType] = string(content)
+	}
+
+	for _, partType := range []string{"text/plain", "text/html"} {
+		content, ok := contents[partType]
+		if !ok {
+			t.Errorf("missing %s part", partType)
+			continue
+		}
+		for _, want := range []string{"*.acme.com", "New targets", "api.initech.com", "Changed targets", "out of scope"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s part does not contain %q", partType, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/e1l1ya/findtarget/internal/httpclient"
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
+)
+
+// maxListedAssets caps the assets listed in a chat message.
+const maxListedAssets = 25
+
+// Notifier sends the changes of a run to a sink. Chat sinks and webhooks only
+// announce the added assets, the email digest lists every change.
+type Notifier interface {
+	// Name returns the sink name used in diagnostics.
+	Name() string
+	// Notify sends the changes, each program holding only its changed assets.
+	Notify(changes store.Changes) error
+}
+
+// Batcher is implemented by notifiers that send a digest at most once per
+// window instead of after every run.
+type Batcher interface {
+	// Window returns the batch window, zero to send after every run.
+	Window() time.Duration
+}
+
+// New creates a notifier for every sink declared in the template. The sinks
+// share the proxy, timeout and headers of the template.
+func New(template *types.Config) ([]Notifier, error) {
+	config := template.Notify
+	if config == nil {
+		return nil, nil
+	}
+
+	// Retries are logged with the request URL, which holds the Telegram token
+	client, err := httpclient.New(httpclient.Options{
+		Proxies: template.Proxy,
+		Timeout: template.Timeout,
+		Headers: template.Headers,
+		Retries: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notification client: %v", err)
+	}
+
+	var notifiers []Notifier
+	if config.Slack != nil {
+		notifiers = append(notifiers, &slack{client: client, config: config.Slack})
+	}
+	if config.Discord != nil {
+		notifiers = append(notifiers, &discord{client: client, config: config.Discord})
+	}
+	if config.Telegram != nil {
+		notifiers = append(notifiers, &telegram{client: client, config: config.Telegram})
+	}
+	if config.Webhook != nil {
+		notifiers = append(notifiers, &webhook{client: client, config: config.Webhook})
+	}
+	if config.Email != nil {
+		email, err := newEmail(config.Email, template)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, email)
+	}
+	return notifiers, nil
+}
+
+// Send notifies every sink of a template, reporting failures without stopping
+// at the first one. Batching sinks queue the changes in the state file and are
+// only notified once their window elapsed; a failed digest stays queued.
+func Send(notifiers []Notifier, state *store.Store, template string, changes store.Changes, now time.Time) {
+	for _, notifier := range notifiers {
+		batcher, ok := notifier.(Batcher)
+		if !ok || batcher.Window() == 0 {
+			if changes.Empty() {
+				continue
+			}
+			if err := notifier.Notify(changes); err != nil {
+				gologger.Warning().Msgf("Failed to notify %s: %v", notifier.Name(), err)
+			}
+			continue
+		}
+
+		digest, err := state.QueueDigest(template, notifier.Name(), changes, now)
+		if err != nil {
+			gologger.Warning().Msgf("Failed to notify %s: %v", notifier.Name(), err)
+			continue
+		}
+		if digest.Empty() || now.Sub(digest.LastSent) < batcher.Window() {
+			continue
+		}
+		if err := notifier.Notify(digest.Changes); err != nil {
+			gologger.Warning().Msgf("Failed to notify %s: %v", notifier.Name(), err)
+			continue
+		}
+		if err := state.ClearDigest(template, notifier.Name(), now); err != nil {
+			gologger.Warning().Msgf("Failed to notify %s: %v", notifier.Name(), err)
+		}
+	}
+}
+
+// message renders the chat message announcing the new assets of a program.
+func message(program types.Program) string {
+	return listing("New targets", program)
+}
+
+// listing renders a program and its assets under a heading such as "New
+// targets".
+func listing(heading string, program types.Program) string {
+	title := program.Name
+	if title == "" {
+		title = program.Handle
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s on %s: %s\n%s\n", heading, program.Platform, title, program.URL)
+	for i, asset := range program.Assets {
+		if i == maxListedAssets {
+			fmt.Fprintf(&text, "... and %d more\n", len(program.Assets)-maxListedAssets)
+			break
+		}
+		var labels string
+		if !asset.InScope {
+			labels += " [out of scope]"
+		}
+		if asset.BountyEligible {
+			labels += " [bounty]"
+		}
+		fmt.Fprintf(&text, "- %s (%s)%s\n", asset.Identifier, asset.Kind, labels)
+	}
+	return text.String()
+}
+
+// postJSON posts v as JSON to endpoint and checks for a successful status.
+func postJSON(client *http.Client, endpoint string, headers map[string]string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %v", withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// withoutURL strips the URL from an error. Webhook URLs and the Telegram bot
+// URL carry the credentials of the sink, so they must not end up in logs.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
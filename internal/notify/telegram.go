@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"net/http"
+
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// telegramMaxLength is the maximum length of a Telegram message.
+const telegramMaxLength = 4096
+
+// telegram sends one message per program through the Telegram bot API.
+type telegram struct {
+	client *http.Client
+	config *types.TelegramSinkConfig
+}
+
+// telegramMessage is the body of a sendMessage call.
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+func (t *telegram) Name() string {
+	return "telegram"
+}
+
+func (t *telegram) Notify(changes store.Changes) error {
+	url := t.config.APIURL + "/bot" + t.config.Token + "/sendMessage"
+	for _, program := range changes.Added {
+		payload := telegramMessage{
+			ChatID:                t.config.ChatID,
+			Text:                  truncate(message(program), telegramMaxLength),
+			DisableWebPagePreview: true,
+		}
+		if err := postJSON(t.client, url, nil, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
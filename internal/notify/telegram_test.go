@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// telegramToken is the bot token used against the test server.
+const telegramToken = "123456:SECRET"
+
+func newTelegram(t *testing.T, apiURL string) Notifier {
+	config := &types.TelegramSinkConfig{Token: telegramToken, ChatID: "-100123", APIURL: apiURL}
+	config.SetDefaults()
+	return newSink(t, &types.NotifyConfig{Telegram: config})
+}
+
+func TestTelegram(t *testing.T) {
+	server := newSinkServer(t, http.StatusOK)
+	if err := newTelegram(t, server.URL).Notify(testChanges); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.requests) != len(testChanges.Added) {
+		t.Fatalf("got %d requests, want %d", len(server.requests), len(testChanges.Added))
+	}
+	for i, req := range server.requests {
+		if req.path != "/bot"+telegramToken+"/sendMessage" {
+			t.Errorf("path = %q", req.path)
+		}
+
+		var payload telegramMessage
+		if err := json.Unmarshal(req.body, &payload); err != nil {
+			t.Fatalf("invalid payload %s: %v", req.body, err)
+		}
+		want := telegramMessage{ChatID: "-100123", Text: message(testChanges.Added[i]), DisableWebPagePreview: true}
+		if payload != want {
+			t.Errorf("payload = %+v, want %+v", payload, want)
+		}
+	}
+}
+
+func TestTelegramErrorsHideToken(t *testing.T) {
+	failing := newSinkServer(t, http.StatusUnauthorized)
+	closed := newSinkServer(t, http.StatusOK)
+	closed.Close()
+
+	for name, apiURL := range map[string]string{"status": failing.URL, "network": closed.URL} {
+		t.Run(name, func(t *testing.T) {
+			err := newTelegram(t, apiURL).Notify(testChanges)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if strings.Contains(err.Error(), "SECRET") {
+				t.Errorf("error leaks the bot token: %v", err)
+			}
+		})
+	}
+}
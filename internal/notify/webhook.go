@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// discordMaxLength is the maximum length of a Discord message.
+const discordMaxLength = 2000
+
+// slack posts one message per program to a Slack incoming webhook.
+type slack struct {
+	client *http.Client
+	config *types.WebhookSinkConfig
+}
+
+func (s *slack) Name() string {
+	return "slack"
+}
+
+func (s *slack) Notify(changes store.Changes) error {
+	for _, program := range changes.Added {
+		payload := map[string]string{"text": message(program)}
+		if err := postJSON(s.client, s.config.URL, s.config.Headers, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// discord posts one message per program to a Discord webhook.
+type discord struct {
+	client *http.Client
+	config *types.WebhookSinkConfig
+}
+
+func (d *discord) Name() string {
+	return "discord"
+}
+
+func (d *discord) Notify(changes store.Changes) error {
+	for _, program := range changes.Added {
+		payload := map[string]string{"content": truncate(message(program), discordMaxLength)}
+		if err := postJSON(d.client, d.config.URL, d.config.Headers, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// webhook posts the new programs and assets as JSON to a generic endpoint.
+type webhook struct {
+	client *http.Client
+	config *types.WebhookSinkConfig
+}
+
+// webhookPayload is the body sent to a generic webhook.
+type webhookPayload struct {
+	Event    string          `json:"event"`
+	Programs []types.Program `json:"programs"`
+}
+
+func (w *webhook) Name() string {
+	return "webhook"
+}
+
+func (w *webhook) Notify(changes store.Changes) error {
+	if len(changes.Added) == 0 {
+		return nil
+	}
+	return postJSON(w.client, w.config.URL, w.config.Headers, webhookPayload{Event: "new_targets", Programs: changes.Added})
+}
+
+// truncate shortens text to at most max bytes without splitting a line.
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[:max-4]
+	if i := strings.LastIndex(text, "\n"); i > 0 {
+		text = text[:i]
+	}
+	return text + "\n..."
+}
@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// testChanges holds two programs with new assets and one with a change only.
+var testChanges = store.Changes{
+	Added: []types.Program{
+		{
+			Platform: "hackerone",
+			Handle:   "acme",
+			Name:     "Acme",
+			URL:      "https://hackerone.com/acme",
+			Assets:   []types.Asset{{Kind: types.AssetWildcard, Identifier: "*.acme.com", InScope: true, BountyEligible: true}},
+		},
+		{
+			Platform: "bugcrowd",
+			Handle:   "initech",
+			URL:      "https://bugcrowd.com/initech",
+			Assets:   []types.Asset{{Kind: types.AssetURL, Identifier: "api.initech.com", InScope: true}},
+		},
+	},
+	Changed: []types.Program{{
+		Platform: "hackerone",
+		Handle:   "globex",
+		Assets:   []types.Asset{{Kind: types.AssetURL, Identifier: "www.globex.com", InScope: true}},
+	}},
+}
+
+// request is a request received by the test sink server.
+type request struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// sinkServer records the requests it receives and answers with status.
+type sinkServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+}
+
+func newSinkServer(t *testing.T, status int) *sinkServer {
+	s := &sinkServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, request{path: r.URL.Path, header: r.Header, body: body})
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newSink creates the single notifier declared by config.
+func newSink(t *testing.T, config *types.NotifyConfig) Notifier {
+	notifiers, err := New(&types.Config{Notify: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifiers) != 1 {
+		t.Fatalf("created %d notifiers, want 1", len(notifiers))
+	}
+	return notifiers[0]
+}
+
+func TestChatWebhooks(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(sink *types.WebhookSinkConfig) *types.NotifyConfig
+		field  string
+	}{
+		{"slack", func(sink *types.WebhookSinkConfig) *types.NotifyConfig { return &types.NotifyConfig{Slack: sink} }, "text"},
+		{"discord", func(sink *types.WebhookSinkConfig) *types.NotifyConfig { return &types.NotifyConfig{Discord: sink} }, "content"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newSinkServer(t, http.StatusNoContent)
+			sink := newSink(t, test.config(&types.WebhookSinkConfig{
+				URL:     server.URL + "/hooks/secret",
+				Headers: map[string]string{"X-Sink": "findtarget"},
+			}))
+			if err := sink.Notify(testChanges); err != nil {
+				t.Fatal(err)
+			}
+
+			// One message per program with new assets
+			if len(server.requests) != len(testChanges.Added) {
+				t.Fatalf("got %d requests, want %d", len(server.requests), len(testChanges.Added))
+			}
+			for i, req := range server.requests {
+				if req.path != "/hooks/secret" {
+					t.Errorf("path = %q", req.path)
+				}
+				if got := req.header.Get("X-Sink"); got != "findtarget" {
+					t.Errorf("X-Sink = %q", got)
+				}
+				if got := req.header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("Content-Type = %q", got)
+				}
+
+				var payload map[string]string
+				if err := json.Unmarshal(req.body, &payload); err != nil {
+					t.Fatalf("invalid payload %s: %v", req.body, err)
+				}
+				if len(payload) != 1 {
+					t.Errorf("payload %s has extra fields", req.body)
+				}
+				program := testChanges.Added[i]
+				if want := message(program); payload[test.field] != want {
+					t.Errorf("%s = %q, want %q", test.field, payload[test.field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenericWebhook(t *testing.T) {
+	server := newSinkServer(t, http.StatusOK)
+	sink := newSink(t, &types.NotifyConfig{Webhook: &types.WebhookSinkConfig{
+		URL:     server.URL + "/findtarget",
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	}})
+
+	if err := sink.Notify(store.Changes{Changed: testChanges.Changed}); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.requests) != 0 {
+		t.Fatalf("changes without new assets were posted")
+	}
+
+	if err := sink.Notify(testChanges); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(server.requests))
+	}
+	req := server.requests[0]
+	if got := req.header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+
+	var payload struct {
+		Event    string          `json:"event"`
+		Programs []types.Program `json:"programs"`
+	}
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("invalid payload %s: %v", req.body, err)
+	}
+	if payload.Event != "new_targets" {
+		t.Errorf("event = %q", payload.Event)
+	}
+	if len(payload.Programs) != 2 || payload.Programs[0].Handle != "acme" || payload.Programs[1].Assets[0].Identifier != "api.initech.com" {
+		t.Errorf("programs = %+v", payload.Programs)
+	}
+}
+
+func TestWebhookErrorsHideURL(t *testing.T) {
+	failing := newSinkServer(t, http.StatusInternalServerError)
+	closed := newSinkServer(t, http.StatusOK)
+	closed.Close()
+
+	for name, url := range map[string]string{
+		"status":  failing.URL + "/hooks/secret-token",
+		"network": closed.URL + "/hooks/secret-token",
+		"invalid": "http://[::1/hooks/secret-token",
+	} {
+		t.Run(name, func(t *testing.T) {
+			sink := newSink(t, &types.NotifyConfig{Webhook: &types.WebhookSinkConfig{URL: url}})
+			err := sink.Notify(testChanges)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if strings.Contains(err.Error(), "secret-token") {
+				t.Errorf("error leaks the webhook URL: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// unsafePathRegex matches the characters not allowed in a directory name.
+var unsafePathRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// dirWriter writes one directory per program, laid out as
+// <dir>/<platform>/<handle>/, holding a target list per asset kind, the
+// out-of-scope assets and the program metadata.
+type dirWriter struct {
+	dir string
+}
+
+// NewDir creates a writer laying out the programs under dir.
+func NewDir(dir string) (Writer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %v", err)
+	}
+	return &dirWriter{dir: dir}, nil
+}
+
+func (w *dirWriter) Write(program types.Program) error {
+	programDir := filepath.Join(w.dir, safePathName(program.Platform), safePathName(program.Handle))
+	if err := os.MkdirAll(programDir, 0755); err != nil {
+		return fmt.Errorf("failed to create program directory: %v", err)
+	}
+
+	var wildcards, urls, ips, outOfScope []string
+	for _, asset := range program.Assets {
+		switch {
+		case !asset.InScope:
+			outOfScope = append(outOfScope, asset.Identifier)
+		case asset.Kind == types.AssetWildcard:
+			wildcards = append(wildcards, asset.Target())
+		case asset.Kind == types.AssetURL:
+			urls = append(urls, asset.Target())
+		case asset.Kind == types.AssetIP:
+			ips = append(ips, asset.Target())
+		}
+	}
+
+	files := map[string][]string{
+		"wildcards.txt":    wildcards,
+		"urls.txt":         urls,
+		"ips.txt":          ips,
+		"out_of_scope.txt": outOfScope,
+	}
+	for name, lines := range files {
+		if err := writeLines(filepath.Join(programDir, name), lines); err != nil {
+			return err
+		}
+	}
+
+	metadata, err := json.MarshalIndent(program, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(programDir, "program.json"), append(metadata, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write program metadata: %v", err)
+	}
+	return nil
+}
+
+func (w *dirWriter) Close() error {
+	return nil
+}
+
+// writeLines writes one entry per line to a file.
+func writeLines(path string, lines []string) error {
+	content := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// safePathName turns a platform or program handle into a directory name.
+func safePathName(name string) string {
+	name = unsafePathRegex.ReplaceAllString(name, "_")
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// jsonWriter writes every record into a single JSON array. The array is
+// streamed so partial results stay valid once the writer is closed.
+type jsonWriter struct {
+	out     io.WriteCloser
+	written bool
+}
+
+func (w *jsonWriter) Write(program types.Program) error {
+	for _, record := range Records(program) {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		prefix := ",\n  "
+		if !w.written {
+			prefix = "[\n  "
+			w.written = true
+		}
+		if _, err := io.WriteString(w.out, prefix+string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if !w.written {
+		end = "[]\n"
+	}
+	if _, err := io.WriteString(w.out, end); err != nil {
+		w.out.Close()
+		return err
+	}
+	return w.out.Close()
+}
+
+// jsonlWriter writes one JSON record per line.
+type jsonlWriter struct {
+	out io.WriteCloser
+}
+
+func (w *jsonlWriter) Write(program types.Program) error {
+	encoder := json.NewEncoder(w.out)
+	for _, record := range Records(program) {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *jsonlWriter) Close() error {
+	return w.out.Close()
+}
@@ -0,0 +1,190 @@
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"unicode"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// Writer writes the programs found by the platforms in a given format.
+type Writer interface {
+	// Write writes a program and its assets.
+	Write(program types.Program) error
+	// Close flushes pending output and closes the destination.
+	Close() error
+}
+
+// Record is a single asset as written by the structured formats.
+type Record struct {
+	Platform    string `json:"platform"`
+	Program     string `json:"program"`
+	ProgramURL  string `json:"program_url"`
+	AssetType   string `json:"asset_type"`
+	Identifier  string `json:"identifier"`
+	Category    string `json:"category,omitempty"`
+	InScope     bool   `json:"in_scope"`
+	Bounty      bool   `json:"bounty"`
+	MaxSeverity string `json:"max_severity,omitempty"`
+	Removed     bool   `json:"removed,omitempty"`
+}
+
+// Records flattens a program into one record per asset.
+func Records(program types.Program) []Record {
+	records := make([]Record, 0, len(program.Assets))
+	for _, asset := range program.Assets {
+		records = append(records, Record{
+			Platform:    program.Platform,
+			Program:     program.Handle,
+			ProgramURL:  program.URL,
+			AssetType:   string(asset.Kind),
+			Identifier:  asset.Identifier,
+			Category:    asset.Category,
+			InScope:     asset.InScope,
+			Bounty:      asset.BountyEligible,
+			MaxSeverity: asset.MaxSeverity,
+			Removed:     asset.Removed,
+		})
+	}
+	return records
+}
+
+// New creates a writer for the format, writing to the file at path or to
+// stdout when path is empty.
+func New(format string, path string) (Writer, error) {
+	out, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "", "txt":
+		return &textWriter{out: out}, nil
+	case "json":
+		return &jsonWriter{out: out}, nil
+	case "jsonl":
+		return &jsonlWriter{out: out}, nil
+	case "csv":
+		return newCSVWriter(out), nil
+	case "markdown", "md":
+		return &markdownWriter{out: out}, nil
+	case "burp":
+		return &burpWriter{scopeCollector{out: out}}, nil
+	case "zap":
+		return &zapWriter{scopeCollector{out: out}}, nil
+	}
+
+	out.Close()
+	return nil, fmt.Errorf("unknown output format: %s", format)
+}
+
+// NewTemplate creates a writer rendering a Go text/template for every
+// record, writing to the file at path or to stdout when path is empty.
+func NewTemplate(text string, path string) (Writer, error) {
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &templateWriter{out: out, tmpl: tmpl}, nil
+}
+
+// open opens the output destination.
+func open(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %v", err)
+	}
+	return file, nil
+}
+
+// nopCloser keeps stdout open when a writer is closed.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// multiWriter writes every program to several writers.
+type multiWriter []Writer
+
+// Multi creates a writer forwarding every program to all the writers.
+func Multi(writers ...Writer) Writer {
+	if len(writers) == 1 {
+		return writers[0]
+	}
+	return multiWriter(writers)
+}
+
+func (m multiWriter) Write(program types.Program) error {
+	for _, writer := range m {
+		if err := writer.Write(program); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiWriter) Close() error {
+	var firstErr error
+	for _, writer := range m {
+		if err := writer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ReadRecords reads the records written by the json or jsonl format.
+func ReadRecords(r io.Reader) ([]Record, error) {
+	reader := bufio.NewReader(r)
+	for {
+		b, err := reader.Peek(1)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			break
+		}
+		reader.ReadByte()
+	}
+
+	decoder := json.NewDecoder(reader)
+	if b, _ := reader.Peek(1); b[0] == '[' {
+		var records []Record
+		if err := decoder.Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to parse records: %v", err)
+		}
+		return records, nil
+	}
+
+	var records []Record
+	for {
+		var record Record
+		err := decoder.Decode(&record)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse records: %v", err)
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,112 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// reportColumns are the columns shared by the CSV and Markdown reports.
+var reportColumns = []string{"Platform", "Program", "Asset Type", "Identifier", "In Scope", "Bounty", "Max Severity", "Program URL", "Removed"}
+
+// reportRow returns the report columns of a record.
+func reportRow(record Record) []string {
+	return []string{
+		record.Platform,
+		record.Program,
+		record.AssetType,
+		record.Identifier,
+		strconv.FormatBool(record.InScope),
+		strconv.FormatBool(record.Bounty),
+		record.MaxSeverity,
+		record.ProgramURL,
+		strconv.FormatBool(record.Removed),
+	}
+}
+
+// csvWriter writes one row per asset, grouped by program.
+type csvWriter struct {
+	out    io.WriteCloser
+	csv    *csv.Writer
+	header bool
+}
+
+func newCSVWriter(out io.WriteCloser) *csvWriter {
+	return &csvWriter{out: out, csv: csv.NewWriter(out)}
+}
+
+func (w *csvWriter) Write(program types.Program) error {
+	if !w.header {
+		if err := w.csv.Write(reportColumns); err != nil {
+			return err
+		}
+		w.header = true
+	}
+
+	for _, record := range Records(program) {
+		if err := w.csv.Write(reportRow(record)); err != nil {
+			return err
+		}
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Close() error {
+	if !w.header {
+		w.csv.Write(reportColumns)
+	}
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		w.out.Close()
+		return err
+	}
+	return w.out.Close()
+}
+
+// markdownWriter writes a section with an asset table for every program.
+// Removed assets are struck through.
+type markdownWriter struct {
+	out io.WriteCloser
+}
+
+func (w *markdownWriter) Write(program types.Program) error {
+	var report strings.Builder
+
+	title := program.Name
+	if title == "" {
+		title = program.Handle
+	}
+	fmt.Fprintf(&report, "## [%s](%s) (%s)\n\n", markdownEscape(title), program.URL, program.Platform)
+	fmt.Fprintf(&report, "| %s |\n", strings.Join(reportColumns[2:7], " | "))
+	fmt.Fprintf(&report, "|%s\n", strings.Repeat(" --- |", 5))
+
+	for _, record := range Records(program) {
+		row := reportRow(record)[2:7]
+		for i := range row {
+			row[i] = markdownEscape(row[i])
+		}
+		if record.Removed {
+			row[1] = "~~" + row[1] + "~~"
+		}
+		fmt.Fprintf(&report, "| %s |\n", strings.Join(row, " | "))
+	}
+	report.WriteString("\n")
+
+	_, err := io.WriteString(w.out, report.String())
+	return err
+}
+
+func (w *markdownWriter) Close() error {
+	return w.out.Close()
+}
+
+// markdownEscape escapes the characters that break a Markdown table cell.
+func markdownEscape(text string) string {
+	replacer := strings.NewReplacer("|", "\\|", "*", "\\*", "_", "\\_", "\n", " ")
+	return replacer.Replace(text)
+}
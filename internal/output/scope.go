@@ -0,0 +1,221 @@
+package output
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// scopeRule is a host based scope rule shared by the Burp Suite and ZAP exports.
+type scopeRule struct {
+	Protocol string // "any", "http" or "https"
+	Host     string // Host quoted for regular expressions
+	Wildcard bool   // Whether the rule matches the subdomains of Host instead
+	Port     string // Port number, empty for any port
+	Path     string // Path prefix, empty for any path
+}
+
+// newScopeRule converts an asset into a scope rule. Wildcards become rules
+// matching every subdomain. Assets that are not hosts are skipped.
+func newScopeRule(asset types.Asset) (scopeRule, bool) {
+	identifier := strings.TrimSpace(asset.Identifier)
+
+	switch asset.Kind {
+	case types.AssetWildcard:
+		domain := strings.TrimPrefix(identifier, "*.")
+		return scopeRule{Protocol: "any", Host: regexp.QuoteMeta(domain), Wildcard: true}, true
+	case types.AssetIP:
+		if net.ParseIP(identifier) == nil {
+			return scopeRule{}, false
+		}
+		return scopeRule{Protocol: "any", Host: regexp.QuoteMeta(identifier)}, true
+	case types.AssetURL:
+	default:
+		return scopeRule{}, false
+	}
+
+	if !strings.Contains(identifier, "://") {
+		identifier = "any://" + identifier
+	}
+	parsedURL, err := url.Parse(identifier)
+	if err != nil || parsedURL.Hostname() == "" {
+		return scopeRule{}, false
+	}
+
+	rule := scopeRule{
+		Protocol: parsedURL.Scheme,
+		Host:     regexp.QuoteMeta(parsedURL.Hostname()),
+		Port:     parsedURL.Port(),
+	}
+	if rule.Protocol != "http" && rule.Protocol != "https" {
+		rule.Protocol = "any"
+	}
+	if parsedURL.Path != "" && parsedURL.Path != "/" {
+		rule.Path = parsedURL.Path
+	}
+	return rule, true
+}
+
+// scopeRules splits the assets of the programs into include and exclude rules.
+func scopeRules(programs []types.Program) (include []scopeRule, exclude []scopeRule) {
+	for _, program := range programs {
+		for _, asset := range program.Assets {
+			rule, ok := newScopeRule(asset)
+			if !ok {
+				continue
+			}
+			if asset.InScope {
+				include = append(include, rule)
+			} else {
+				exclude = append(exclude, rule)
+			}
+		}
+	}
+	return include, exclude
+}
+
+// scopeCollector buffers the programs until the scope file can be written.
+type scopeCollector struct {
+	out      io.WriteCloser
+	programs []types.Program
+}
+
+func (c *scopeCollector) Write(program types.Program) error {
+	c.programs = append(c.programs, program)
+	return nil
+}
+
+// burpWriter writes a Burp Suite project options file with an advanced scope.
+type burpWriter struct {
+	scopeCollector
+}
+
+type burpRule struct {
+	Enabled  bool   `json:"enabled"`
+	File     string `json:"file"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+type burpProjectOptions struct {
+	Target struct {
+		Scope struct {
+			AdvancedMode bool       `json:"advanced_mode"`
+			Exclude      []burpRule `json:"exclude"`
+			Include      []burpRule `json:"include"`
+		} `json:"scope"`
+	} `json:"target"`
+}
+
+// newBurpRule converts a scope rule into Burp Suite regexes.
+func newBurpRule(rule scopeRule) burpRule {
+	burp := burpRule{Enabled: true, Host: "^" + rule.Host + "$", Protocol: rule.Protocol}
+	if rule.Wildcard {
+		burp.Host = `^.*\.` + rule.Host + "$"
+	}
+	if rule.Port != "" {
+		burp.Port = "^" + rule.Port + "$"
+	}
+	if rule.Path != "" {
+		burp.File = "^" + regexp.QuoteMeta(rule.Path) + ".*"
+	}
+	return burp
+}
+
+func (w *burpWriter) Close() error {
+	include, exclude := scopeRules(w.programs)
+
+	var options burpProjectOptions
+	options.Target.Scope.AdvancedMode = true
+	options.Target.Scope.Include = []burpRule{}
+	options.Target.Scope.Exclude = []burpRule{}
+	for _, rule := range include {
+		options.Target.Scope.Include = append(options.Target.Scope.Include, newBurpRule(rule))
+	}
+	for _, rule := range exclude {
+		options.Target.Scope.Exclude = append(options.Target.Scope.Exclude, newBurpRule(rule))
+	}
+
+	encoder := json.NewEncoder(w.out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(options); err != nil {
+		w.out.Close()
+		return err
+	}
+	return w.out.Close()
+}
+
+// zapWriter writes a ZAP context file.
+type zapWriter struct {
+	scopeCollector
+}
+
+type zapContext struct {
+	XMLName xml.Name `xml:"configuration"`
+	Context struct {
+		Name       string   `xml:"name"`
+		Desc       string   `xml:"desc"`
+		InScope    bool     `xml:"inscope"`
+		IncRegexes []string `xml:"incregexes"`
+		ExcRegexes []string `xml:"excregexes"`
+	} `xml:"context"`
+}
+
+// zapRegex converts a scope rule into a ZAP URL regex.
+func zapRegex(rule scopeRule) string {
+	protocol := "https?"
+	if rule.Protocol != "any" {
+		protocol = rule.Protocol
+	}
+
+	port := `(:[0-9]+)?`
+	if rule.Port != "" {
+		port = ":" + rule.Port
+	}
+
+	path := `(/.*)?`
+	if rule.Path != "" {
+		path = regexp.QuoteMeta(rule.Path) + ".*"
+	}
+
+	// The subdomain labels cannot hold the characters ending a host, so a
+	// wildcard cannot match a host merely followed by the domain in the URL
+	host := rule.Host
+	if rule.Wildcard {
+		host = `([a-zA-Z0-9-]+\.)+` + host
+	}
+	return "^" + protocol + "://" + host + port + path + "$"
+}
+
+func (w *zapWriter) Close() error {
+	include, exclude := scopeRules(w.programs)
+
+	var context zapContext
+	context.Context.Name = "findtarget"
+	context.Context.Desc = "Scope exported by findtarget"
+	context.Context.InScope = true
+	for _, rule := range include {
+		context.Context.IncRegexes = append(context.Context.IncRegexes, zapRegex(rule))
+	}
+	for _, rule := range exclude {
+		context.Context.ExcRegexes = append(context.Context.ExcRegexes, zapRegex(rule))
+	}
+
+	data, err := xml.MarshalIndent(context, "", "  ")
+	if err != nil {
+		w.out.Close()
+		return err
+	}
+	if _, err := io.WriteString(w.out, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n"+string(data)+"\n"); err != nil {
+		w.out.Close()
+		return err
+	}
+	return w.out.Close()
+}
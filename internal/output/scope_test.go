@@ -0,0 +1,40 @@
+package output
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+func TestZapRegexWildcard(t *testing.T) {
+	rule, ok := newScopeRule(types.Asset{Kind: types.AssetWildcard, Identifier: "*.example.com"})
+	if !ok {
+		t.Fatal("wildcard asset was skipped")
+	}
+	re := regexp.MustCompile(zapRegex(rule))
+
+	for _, u := range []string{"https://www.example.com", "http://a.b.example.com:8443/path"} {
+		if !re.MatchString(u) {
+			t.Errorf("%s should match %s", re, u)
+		}
+	}
+	for _, u := range []string{"https://evil.com/x.example.com", "https://evil.com/?q=.example.com", "https://evil.com#.example.com", "https://example.com.evil.com"} {
+		if re.MatchString(u) {
+			t.Errorf("%s should not match %s", re, u)
+		}
+	}
+}
+
+func TestBurpRuleWildcard(t *testing.T) {
+	rule, _ := newScopeRule(types.Asset{Kind: types.AssetWildcard, Identifier: "*.example.com"})
+	if got := newBurpRule(rule).Host; got != `^.*\.example\.com$` {
+		t.Errorf("unexpected Burp host regex %q", got)
+	}
+
+	rule, _ = newScopeRule(types.Asset{Kind: types.AssetURL, Identifier: "https://app.example.com:8443/api"})
+	burp := newBurpRule(rule)
+	if burp.Host != `^app\.example\.com$` || burp.Port != "^8443$" || burp.Protocol != "https" {
+		t.Errorf("unexpected Burp rule %+v", burp)
+	}
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// templateFuncs are the helpers available to output templates.
+var templateFuncs = template.FuncMap{
+	"trimWildcard": func(identifier string) string {
+		return strings.TrimPrefix(identifier, "*.")
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseTemplate parses a user supplied output template.
+func parseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// templateWriter renders a template for every record and writes one line per
+// record. Records rendering to an empty string are skipped, so templates can
+// filter with {{if}}.
+type templateWriter struct {
+	out  io.WriteCloser
+	tmpl *template.Template
+}
+
+func (w *templateWriter) Write(program types.Program) error {
+	var line bytes.Buffer
+	for _, record := range Records(program) {
+		line.Reset()
+		if err := w.tmpl.Execute(&line, record); err != nil {
+			return fmt.Errorf("failed to render output template: %v", err)
+		}
+
+		text := strings.TrimRight(line.String(), "\n")
+		if text == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(w.out, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *templateWriter) Close() error {
+	return w.out.Close()
+}
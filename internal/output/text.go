@@ -0,0 +1,31 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// textWriter prints one in-scope target per line. Removed targets are
+// prefixed with "- ".
+type textWriter struct {
+	out io.WriteCloser
+}
+
+func (w *textWriter) Write(program types.Program) error {
+	for _, asset := range program.InScopeAssets() {
+		line := asset.Target()
+		if asset.Removed {
+			line = "- " + line
+		}
+		if _, err := fmt.Fprintln(w.out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *textWriter) Close() error {
+	return w.out.Close()
+}
@@ -1,16 +1,17 @@
 package platform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/e1l1ya/findtarget/pkg/types"
 	"golang.org/x/net/html"
-	"golang.org/x/net/proxy"
 )
 
 const bugcrowdBaseURL = "https://bugcrowd.com"
@@ -22,47 +23,27 @@ func isURL(str string) bool {
 }
 
 // constructBaseURL builds the base URL with query parameters.
-func constructBaseURL(config *types.Config) string {
+func constructBaseURL(config *types.BugCrowdConfig) string {
 	baseURL := bugcrowdBaseURL + "/engagements.json?&page=%d"
 
-	if config.FindTarget.BugCrowd.Category != "" {
-		baseURL += fmt.Sprintf("&target_categories=%s", config.FindTarget.BugCrowd.Category)
+	if config.Category != "" {
+		baseURL += fmt.Sprintf("&target_categories=%s", config.Category)
 	}
 
-	if config.FindTarget.BugCrowd.Reward != "" {
-		if config.FindTarget.BugCrowd.Reward == "points" {
+	if config.Reward != "" {
+		if config.Reward == "points" {
 			baseURL += "&category=vdp"
 		} else {
-			baseURL += fmt.Sprintf("&category=bug_bounty&rewards_operator=gte&rewards_amount=%s", config.FindTarget.BugCrowd.Reward)
+			baseURL += fmt.Sprintf("&category=bug_bounty&rewards_operator=gte&rewards_amount=%s", config.Reward)
 		}
 	}
 
 	return baseURL
 }
 
-// createHTTPClient creates an HTTP client with optional SOCKS5 proxy support.
-func createHTTPClient(proxyURL string) (*http.Client, error) {
-	if proxyURL == "" {
-		return &http.Client{}, nil
-	}
-
-	parsedProxyURL, err := url.Parse(proxyURL)
-	if err != nil {
-		return nil, fmt.Errorf("invalid proxy URL: %v", err)
-	}
-
-	dialer, err := proxy.SOCKS5("tcp", parsedProxyURL.Host, nil, proxy.Direct)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create SOCKS5 proxy dialer: %v", err)
-	}
-
-	transport := &http.Transport{Dial: dialer.Dial}
-	return &http.Client{Transport: transport}, nil
-}
-
 // fetchBriefVersionDocument fetches the brief version document URL from a program page.
-func fetchBriefVersionDocument(client *http.Client, programURL string) (string, error) {
-	resp, err := client.Get(programURL)
+func fetchBriefVersionDocument(ctx context.Context, client *http.Client, programURL string) (string, error) {
+	resp, err := get(ctx, client, programURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch program page: %v", err)
 	}
@@ -101,8 +82,8 @@ func fetchBriefVersionDocument(client *http.Client, programURL string) (string,
 }
 
 // fetchScopeItems fetches and parses the scope items from the brief document JSON.
-func fetchScopeItems(client *http.Client, briefDocumentURL string) ([]types.ScopeItem, error) {
-	resp, err := client.Get(briefDocumentURL)
+func fetchScopeItems(ctx context.Context, client *http.Client, briefDocumentURL string) ([]types.ScopeItem, error) {
+	resp, err := get(ctx, client, briefDocumentURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch brief version document: %v", err)
 	}
@@ -132,141 +113,142 @@ func extractURL(text string) string {
 	return text
 }
 
-// processTarget processes and prints target names based on the configuration.
-func processTarget(config *types.Config, target types.Target) bool {
+// processTarget converts a Bugcrowd target into an asset. Names and URIs are
+// preferred over the category so hosts listed under "api" or "other" can still
+// be selected by the scope mode.
+func processTarget(item types.ScopeItem, target types.Target) types.Asset {
 	name := extractURL(target.Name)
 	uri := extractURL(target.URI)
 
-	if config.FindTarget.BugCrowd.Scope == "narrow" {
-		if isURL(name) && !strings.HasPrefix(name, "*") {
-			fmt.Println(name)
-			return true
-		} else if isURL(uri) && !strings.HasPrefix(name, "*") {
-			fmt.Println(uri)
-			return true
-		}
-	} else if config.FindTarget.BugCrowd.Scope == "wide" && strings.HasPrefix(name, "*.") {
-		widescope := strings.TrimPrefix(name, "*.")
-		if !strings.Contains(widescope, "*") {
-			fmt.Println(widescope)
-			return true
-		}
-	} else if config.FindTarget.BugCrowd.Scope == "all" {
-		if isURL(name) && !strings.HasPrefix(name, "*") {
-			fmt.Println(name)
-			return true
-		} else if isURL(uri) && !strings.HasPrefix(name, "*") {
-			fmt.Println(uri)
-			return true
-		} else if strings.HasPrefix(name, "*.") {
-			widescope := strings.TrimPrefix(name, "*.")
-			if !strings.Contains(widescope, "*") {
-				fmt.Println(widescope)
-				return true
-			}
-		}
+	asset := types.Asset{
+		Kind:           categoryKind(target.Category),
+		Type:           target.Category,
+		Category:       target.Category,
+		Identifier:     name,
+		InScope:        item.IsInScope(),
+		BountyEligible: item.IsInScope() && item.HasRewards(),
+		Instruction:    target.Description,
+	}
+
+	if strings.HasPrefix(name, "*.") {
+		asset.Kind = classifyHost(name)
+	} else if isURL(name) && !strings.HasPrefix(name, "*") {
+		asset.Kind = types.AssetURL
+	} else if isURL(uri) && !strings.HasPrefix(name, "*") {
+		asset.Kind = types.AssetURL
+		asset.Identifier = uri
 	}
 
-	return false
+	return asset
 }
 
-// Bugcrowd fetches Bugcrowd data, extracts engagement details, and fetches additional API endpoints.
-func Bugcrowd(config *types.Config) error {
-	baseURL := constructBaseURL(config)
+// categoryKind maps a Bugcrowd target category to an asset kind.
+func categoryKind(category string) types.AssetKind {
+	switch category {
+	case "android", "ios":
+		return types.AssetMobile
+	case "hardware", "iot":
+		return types.AssetHardware
+	case "network":
+		return types.AssetIP
+	}
+	return types.AssetOther
+}
 
-	client, err := createHTTPClient(config.Proxy)
-	if err != nil {
-		return err
-	}
+// bugcrowd scans public Bugcrowd engagements.
+type bugcrowd struct {
+	config  *types.BugCrowdConfig
+	client  *http.Client
+	baseURL string
+}
 
-	// Check if the config has an "Include" array
-	if len(config.FindTarget.BugCrowd.Include) > 0 {
-		for _, includeURL := range config.FindTarget.BugCrowd.Include {
-			// Fetch the brief version document for each URL in the "Include" array
-			briefVersionDocument, err := fetchBriefVersionDocument(client, includeURL)
-			if err != nil {
-				fmt.Printf("Failed to fetch brief version document for %s: %v\n", includeURL, err)
-				continue
-			}
+func init() {
+	Register(func() Platform { return &bugcrowd{} })
+}
 
-			// Fetch and process scope items
-			scopeItems, err := fetchScopeItems(client, briefVersionDocument+".json")
-			if err != nil {
-				fmt.Printf("Failed to fetch scope items for %s: %v\n", includeURL, err)
-				continue
-			}
+// Name returns the template key of Bugcrowd.
+func (b *bugcrowd) Name() string {
+	return "bugcrowd"
+}
 
-			for _, item := range scopeItems {
-				for _, target := range item.Targets {
-					if config.FindTarget.BugCrowd.Category == "" || config.FindTarget.BugCrowd.Category == target.Category {
-						processTarget(config, target)
-					}
-				}
-			}
-		}
-		return nil // Skip the paginated section if "Include" is used
+// Configure prepares the HTTP client and engagement listing URL.
+func (b *bugcrowd) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.BugCrowd == nil {
+		return nil, nil
 	}
 
-	// Paginated section
-	page := 1
-	totalPages := 1
-	limit := int16(0)
+	client, err := newHTTPClient(config, &config.FindTarget.BugCrowd.PlatformConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	for page <= totalPages {
-		url := fmt.Sprintf(baseURL, page)
+	b.config = config.FindTarget.BugCrowd
+	b.client = client
+	b.baseURL = constructBaseURL(b.config)
+	return &b.config.PlatformConfig, nil
+}
 
-		resp, err := client.Get(url)
-		if err != nil {
-			return fmt.Errorf("failed to fetch data from Bugcrowd: %v", err)
+// Programs returns one page of engagements, or the include list when set.
+func (b *bugcrowd) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	// Check if the config has an "Include" array
+	if len(b.config.Include) > 0 {
+		programs := make([]types.Program, 0, len(b.config.Include))
+		for _, includeURL := range b.config.Include {
+			programs = append(programs, types.Program{Handle: path.Base(includeURL), URL: includeURL})
 		}
-		defer resp.Body.Close()
+		return programs, false, nil
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("unexpected response from Bugcrowd: %d", resp.StatusCode)
-		}
+	resp, err := get(ctx, b.client, fmt.Sprintf(b.baseURL, page))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch data from Bugcrowd: %v", err)
+	}
+	defer resp.Body.Close()
 
-		var data types.APIResponse
-		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-			return fmt.Errorf("failed to parse Bugcrowd JSON: %v", err)
-		}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected response from Bugcrowd: %d", resp.StatusCode)
+	}
 
-		if page == 1 && data.PaginationMeta.Limit > 0 {
-			totalPages = int(math.Ceil(float64(data.PaginationMeta.TotalCount) / float64(data.PaginationMeta.Limit)))
-		}
+	var data types.APIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, false, fmt.Errorf("failed to parse Bugcrowd JSON: %v", err)
+	}
 
-		for _, engagement := range data.Engagements {
-			programURL := bugcrowdBaseURL + engagement.BriefURL
-			briefVersionDocument, err := fetchBriefVersionDocument(client, programURL)
+	totalPages := 1
+	if data.PaginationMeta.Limit > 0 {
+		totalPages = int(math.Ceil(float64(data.PaginationMeta.TotalCount) / float64(data.PaginationMeta.Limit)))
+	}
 
-			if config.FindTarget.BugCrowd.MaxPrograms != 0 && limit >= config.FindTarget.BugCrowd.MaxPrograms {
-				return nil
-			}
+	programs := make([]types.Program, 0, len(data.Engagements))
+	for _, engagement := range data.Engagements {
+		programs = append(programs, types.Program{
+			Handle: path.Base(engagement.BriefURL),
+			Name:   engagement.Name,
+			URL:    bugcrowdBaseURL + engagement.BriefURL,
+		})
+	}
 
-			if err != nil {
-				continue
-			}
+	return programs, page < totalPages, nil
+}
 
-			scopeItems, err := fetchScopeItems(client, briefVersionDocument+".json")
-			if err != nil {
-				continue
-			}
+// Scope fetches the brief document of an engagement and returns its targets.
+func (b *bugcrowd) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	briefVersionDocument, err := fetchBriefVersionDocument(ctx, b.client, program.URL)
+	if err != nil {
+		return nil, err
+	}
 
-			for _, item := range scopeItems {
-				for _, target := range item.Targets {
-					if config.FindTarget.BugCrowd.Category == "" || config.FindTarget.BugCrowd.Category == target.Category {
-						if processTarget(config, target) {
-							limit++
-							if config.FindTarget.BugCrowd.MaxPrograms != 0 && limit >= config.FindTarget.BugCrowd.MaxPrograms {
-								return nil
-							}
-						}
-					}
-				}
-			}
-		}
+	scopeItems, err := fetchScopeItems(ctx, b.client, briefVersionDocument+".json")
+	if err != nil {
+		return nil, err
+	}
 
-		page++
+	var assets []types.Asset
+	for _, item := range scopeItems {
+		for _, target := range item.Targets {
+			assets = append(assets, processTarget(item, target))
+		}
 	}
 
-	return nil
+	return assets, nil
 }
@@ -0,0 +1,105 @@
+package platform
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// custom serves the private programs and engagements declared in the template.
+type custom struct {
+	config *types.CustomConfig
+}
+
+func init() {
+	Register(func() Platform { return &custom{} })
+}
+
+// Name returns the template key of the custom programs.
+func (c *custom) Name() string {
+	return "custom"
+}
+
+// Configure loads the custom programs from the template.
+func (c *custom) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.Custom == nil {
+		return nil, nil
+	}
+
+	c.config = config.FindTarget.Custom
+	return &c.config.PlatformConfig, nil
+}
+
+// Programs returns the custom programs matching the include and reward filters.
+func (c *custom) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	var programs []types.Program
+	for _, declared := range c.config.Programs {
+		if !c.matchInclude(declared) || !c.matchReward(declared) {
+			continue
+		}
+
+		program := types.Program{Handle: declared.Name, Name: declared.Name, URL: declared.URL}
+		for _, identifier := range declared.InScope {
+			program.Assets = append(program.Assets, customAsset(identifier, true, declared.Bounty))
+		}
+		for _, identifier := range declared.OutOfScope {
+			program.Assets = append(program.Assets, customAsset(identifier, false, false))
+		}
+		programs = append(programs, program)
+	}
+
+	return programs, false, nil
+}
+
+// Scope returns the assets declared for the program.
+func (c *custom) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	return program.Assets, nil
+}
+
+// matchInclude reports whether a program is in the include list.
+func (c *custom) matchInclude(program types.CustomProgram) bool {
+	if len(c.config.Include) == 0 {
+		return true
+	}
+	for _, include := range c.config.Include {
+		if strings.EqualFold(include, program.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchReward applies the reward filter of the template. Custom programs do
+// not declare amounts, so any amount selects the programs paying bounties.
+func (c *custom) matchReward(program types.CustomProgram) bool {
+	switch c.config.Reward {
+	case "":
+		return true
+	case "points":
+		return !program.Bounty
+	default:
+		return program.Bounty
+	}
+}
+
+// customAsset classifies an identifier declared in the template.
+func customAsset(identifier string, inScope bool, bounty bool) types.Asset {
+	identifier = strings.TrimSpace(identifier)
+	asset := types.Asset{
+		Kind:           classifyHost(identifier),
+		Type:           "custom",
+		Category:       "website",
+		Identifier:     identifier,
+		InScope:        inScope,
+		BountyEligible: inScope && bounty,
+	}
+
+	if _, _, err := net.ParseCIDR(identifier); err == nil || net.ParseIP(identifier) != nil {
+		asset.Kind = types.AssetIP
+		asset.Category = "network"
+	}
+
+	return asset
+}
@@ -0,0 +1,276 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// datasetFiles maps the file names of the bounty-targets dumps to their loaders.
+var datasetFiles = map[string]func(data []byte) ([]datasetProgram, error){
+	"hackerone_data.json": loadHackerOneDataset,
+	"bugcrowd_data.json":  loadBugcrowdDataset,
+	"intigriti_data.json": loadIntigritiDataset,
+	"yeswehack_data.json": loadYesWeHackDataset,
+}
+
+// datasetProgram is a program read from a dump with the data needed by the
+// reward filter.
+type datasetProgram struct {
+	program   types.Program
+	bounty    bool
+	maxBounty float64 // Zero when the dump does not publish amounts
+}
+
+// file reads programs from local bounty-targets dumps instead of the
+// platform APIs, so findtarget can run without network access.
+type file struct {
+	config    *types.FileConfig
+	minReward float64
+}
+
+func init() {
+	Register(func() Platform { return &file{} })
+}
+
+// Name returns the template key of the dataset import.
+func (f *file) Name() string {
+	return "file"
+}
+
+// Configure validates the dataset path and the reward filter.
+func (f *file) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.File == nil {
+		return nil, nil
+	}
+
+	f.config = config.FindTarget.File
+	if f.config.Path == "" {
+		return nil, fmt.Errorf("no dataset path provided")
+	}
+
+	if f.config.Reward != "" && f.config.Reward != "points" {
+		var err error
+		f.minReward, err = strconv.ParseFloat(f.config.Reward, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reward %q: %v", f.config.Reward, err)
+		}
+	}
+
+	return &f.config.PlatformConfig, nil
+}
+
+// Programs reads every dump found at the configured path. The dumps carry the
+// scopes of the programs, so they are attached right away.
+func (f *file) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	paths, err := datasetPaths(f.config.Path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var programs []types.Program
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read dataset: %v", err)
+		}
+
+		loaded, err := datasetFiles[filepath.Base(path)](data)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+
+		for _, entry := range loaded {
+			if f.matchInclude(entry.program) && f.matchReward(entry) {
+				programs = append(programs, entry.program)
+			}
+		}
+	}
+
+	return programs, false, nil
+}
+
+// Scope returns the assets attached to the program by Programs.
+func (f *file) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	return program.Assets, nil
+}
+
+// matchInclude reports whether a program is in the include list, matching
+// either its handle or its URL.
+func (f *file) matchInclude(program types.Program) bool {
+	if len(f.config.Include) == 0 {
+		return true
+	}
+	for _, include := range f.config.Include {
+		if strings.EqualFold(include, program.Handle) || strings.EqualFold(strings.TrimSuffix(include, "/"), strings.TrimSuffix(program.URL, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchReward applies the reward filter of the template to a program.
+func (f *file) matchReward(entry datasetProgram) bool {
+	switch {
+	case f.config.Reward == "":
+		return true
+	case f.config.Reward == "points":
+		return !entry.bounty
+	case entry.maxBounty == 0:
+		// The dump only tells whether the program pays
+		return entry.bounty
+	default:
+		return entry.maxBounty >= f.minReward
+	}
+}
+
+// datasetPaths returns the dumps to read. A directory is searched for the
+// well-known dump names, any other path is read as a single dump.
+func datasetPaths(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("dataset not found: %s", path)
+	}
+
+	if !info.IsDir() {
+		if _, ok := datasetFiles[filepath.Base(path)]; !ok {
+			return nil, fmt.Errorf("unknown dataset file: %s", path)
+		}
+		return []string{path}, nil
+	}
+
+	var paths []string
+	for _, name := range []string{"bugcrowd_data.json", "hackerone_data.json", "intigriti_data.json", "yeswehack_data.json"} {
+		if _, err := os.Stat(filepath.Join(path, name)); err == nil {
+			paths = append(paths, filepath.Join(path, name))
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no dataset found in %s", path)
+	}
+	return paths, nil
+}
+
+// loadHackerOneDataset converts hackerone_data.json.
+func loadHackerOneDataset(data []byte) ([]datasetProgram, error) {
+	var dataset []types.HackerOneDataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, err
+	}
+
+	programs := make([]datasetProgram, 0, len(dataset))
+	for _, entry := range dataset {
+		program := types.Program{Platform: "hackerone", Handle: entry.Handle, Name: entry.Name, URL: entry.URL}
+		for _, target := range entry.Targets.InScope {
+			program.Assets = append(program.Assets, hackerOneAssets(target, true)...)
+		}
+		for _, target := range entry.Targets.OutOfScope {
+			program.Assets = append(program.Assets, hackerOneAssets(target, false)...)
+		}
+		programs = append(programs, datasetProgram{program: program, bounty: entry.OffersBounties})
+	}
+	return programs, nil
+}
+
+// loadBugcrowdDataset converts bugcrowd_data.json.
+func loadBugcrowdDataset(data []byte) ([]datasetProgram, error) {
+	var dataset []types.BugcrowdDataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, err
+	}
+
+	inScope, outOfScope := true, false
+	programs := make([]datasetProgram, 0, len(dataset))
+	for _, entry := range dataset {
+		handle := filepath.Base(strings.TrimSuffix(entry.URL, "/"))
+		program := types.Program{Platform: "bugcrowd", Handle: handle, Name: entry.Name, URL: entry.URL}
+		for _, target := range entry.Targets.InScope {
+			asset := processTarget(types.ScopeItem{InScope: &inScope}, types.Target{Name: target.Target, URI: target.URI, Category: target.Type})
+			asset.BountyEligible = entry.MaxPayout > 0
+			program.Assets = append(program.Assets, asset)
+		}
+		for _, target := range entry.Targets.OutOfScope {
+			program.Assets = append(program.Assets, processTarget(types.ScopeItem{InScope: &outOfScope}, types.Target{Name: target.Target, URI: target.URI, Category: target.Type}))
+		}
+		programs = append(programs, datasetProgram{program: program, bounty: entry.MaxPayout > 0, maxBounty: float64(entry.MaxPayout)})
+	}
+	return programs, nil
+}
+
+// loadIntigritiDataset converts intigriti_data.json.
+func loadIntigritiDataset(data []byte) ([]datasetProgram, error) {
+	var dataset []types.IntigritiDataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, err
+	}
+
+	programs := make([]datasetProgram, 0, len(dataset))
+	for _, entry := range dataset {
+		program := types.Program{Platform: "intigriti", ID: entry.ID, Handle: entry.Handle, Name: entry.Name, URL: entry.URL}
+		for _, target := range entry.Targets.InScope {
+			program.Assets = append(program.Assets, intigritiDatasetAsset(target, true, entry.MaxBounty.Value > 0))
+		}
+		for _, target := range entry.Targets.OutOfScope {
+			program.Assets = append(program.Assets, intigritiDatasetAsset(target, false, false))
+		}
+		programs = append(programs, datasetProgram{program: program, bounty: entry.MaxBounty.Value > 0, maxBounty: entry.MaxBounty.Value})
+	}
+	return programs, nil
+}
+
+// intigritiDatasetAsset converts a target of intigriti_data.json.
+func intigritiDatasetAsset(target types.IntigritiDatasetTarget, inScope bool, bounty bool) types.Asset {
+	identifier := extractURL(strings.TrimSpace(target.Endpoint))
+	return types.Asset{
+		Kind:           intigritiKind(target.Type, identifier),
+		Type:           target.Type,
+		Category:       intigritiCategory(target.Type),
+		Identifier:     identifier,
+		InScope:        inScope,
+		BountyEligible: inScope && bounty,
+		Instruction:    target.Description,
+	}
+}
+
+// loadYesWeHackDataset converts yeswehack_data.json.
+func loadYesWeHackDataset(data []byte) ([]datasetProgram, error) {
+	var dataset []types.YesWeHackDataset
+	if err := json.Unmarshal(data, &dataset); err != nil {
+		return nil, err
+	}
+
+	programs := make([]datasetProgram, 0, len(dataset))
+	for _, entry := range dataset {
+		if entry.Disabled {
+			continue
+		}
+		program := types.Program{Platform: "yeswehack", Handle: entry.ID, Name: entry.Name, URL: "https://yeswehack.com/programs/" + entry.ID}
+		for _, target := range entry.Targets.InScope {
+			program.Assets = append(program.Assets, yesWeHackDatasetAsset(target, true, entry.MaxBounty > 0))
+		}
+		for _, target := range entry.Targets.OutOfScope {
+			program.Assets = append(program.Assets, yesWeHackDatasetAsset(target, false, false))
+		}
+		programs = append(programs, datasetProgram{program: program, bounty: entry.MaxBounty > 0, maxBounty: entry.MaxBounty})
+	}
+	return programs, nil
+}
+
+// yesWeHackDatasetAsset converts a target of yeswehack_data.json.
+func yesWeHackDatasetAsset(target types.YesWeHackDatasetTarget, inScope bool, bounty bool) types.Asset {
+	identifier := extractURL(strings.TrimSpace(target.Target))
+	return types.Asset{
+		Kind:           yesWeHackKind(target.Type, identifier),
+		Type:           target.Type,
+		Category:       yesWeHackCategory(target.Type),
+		Identifier:     identifier,
+		InScope:        inScope,
+		BountyEligible: inScope && bounty,
+	}
+}
@@ -0,0 +1,234 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// datasetDir holds one small dump of every supported platform.
+const datasetDir = "testdata/datasets"
+
+// describeAssets renders assets as "kind identifier [in] [bounty]" lines so
+// expectations stay readable.
+func describeAssets(assets []types.Asset) []string {
+	var lines []string
+	for _, asset := range assets {
+		line := string(asset.Kind) + " " + asset.Identifier
+		if asset.InScope {
+			line += " in"
+		}
+		if asset.BountyEligible {
+			line += " bounty"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestLoadDatasets(t *testing.T) {
+	type program struct {
+		handle    string
+		bounty    bool
+		maxBounty float64
+		assets    []string
+	}
+	tests := []struct {
+		file     string
+		platform string
+		want     []program
+	}{
+		{
+			file:     "hackerone_data.json",
+			platform: "hackerone",
+			want: []program{
+				{handle: "acme", bounty: true, assets: []string{
+					"wildcard *.acme.com in bounty",
+					"url api.acme.com in",
+					"url www.acme.com in",
+					"mobile com.acme.app in bounty",
+					"url blog.acme.com",
+				}},
+				{handle: "vdp", assets: []string{"url vdp.example.org in"}},
+			},
+		},
+		{
+			file:     "bugcrowd_data.json",
+			platform: "bugcrowd",
+			want: []program{
+				{handle: "initech", bounty: true, maxBounty: 5000, assets: []string{
+					"wildcard *.initech.com in bounty",
+					"url https://api.initech.com in bounty",
+					"mobile com.initech.app in bounty",
+					"other status.initech.com",
+				}},
+				{handle: "globex", assets: []string{"other www.globex.com in"}},
+			},
+		},
+		{
+			file:     "intigriti_data.json",
+			platform: "intigriti",
+			want: []program{
+				{handle: "umbrellacorp", bounty: true, maxBounty: 1500, assets: []string{
+					"wildcard *.umbrella.com in bounty",
+					"ip 10.0.0.0/24 in bounty",
+					"url legacy.umbrella.com",
+				}},
+			},
+		},
+		{
+			file:     "yeswehack_data.json",
+			platform: "yeswehack",
+			want: []program{
+				{handle: "hooli", bounty: true, maxBounty: 800, assets: []string{
+					"url https://www.hooli.com in bounty",
+					"mobile com.hooli.app in bounty",
+					"wildcard *.dev.hooli.com",
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(datasetDir, test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			loaded, err := datasetFiles[test.file](data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(loaded) != len(test.want) {
+				t.Fatalf("loaded %d programs, want %d", len(loaded), len(test.want))
+			}
+			for i, entry := range loaded {
+				want := test.want[i]
+				if entry.program.Platform != test.platform || entry.program.Handle != want.handle {
+					t.Errorf("program %d = %s/%s, want %s/%s", i, entry.program.Platform, entry.program.Handle, test.platform, want.handle)
+				}
+				if entry.bounty != want.bounty || entry.maxBounty != want.maxBounty {
+					t.Errorf("%s: bounty = %t/%v, want %t/%v", want.handle, entry.bounty, entry.maxBounty, want.bounty, want.maxBounty)
+				}
+				if got := describeAssets(entry.program.Assets); fmt.Sprint(got) != fmt.Sprint(want.assets) {
+					t.Errorf("%s: assets = %q, want %q", want.handle, got, want.assets)
+				}
+			}
+		})
+	}
+}
+
+func TestFilePrograms(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings types.PlatformConfig
+		want     []string
+	}{
+		{
+			name: "all",
+			want: []string{"bugcrowd/initech", "bugcrowd/globex", "hackerone/acme", "hackerone/vdp", "intigriti/umbrellacorp", "yeswehack/hooli"},
+		},
+		{
+			// HackerOne dumps carry no amounts, so paying programs always match
+			name:     "reward",
+			settings: types.PlatformConfig{Reward: "1000"},
+			want:     []string{"bugcrowd/initech", "hackerone/acme", "intigriti/umbrellacorp"},
+		},
+		{
+			name:     "points",
+			settings: types.PlatformConfig{Reward: "points"},
+			want:     []string{"bugcrowd/globex", "hackerone/vdp"},
+		},
+		{
+			name:     "include",
+			settings: types.PlatformConfig{Include: []string{"ACME", "https://bugcrowd.com/globex/"}},
+			want:     []string{"bugcrowd/globex", "hackerone/acme"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &types.Config{}
+			config.FindTarget.File = &types.FileConfig{PlatformConfig: test.settings, Path: datasetDir}
+			config.SetDefaults()
+
+			platform := &file{}
+			if _, err := platform.Configure(config); err != nil {
+				t.Fatal(err)
+			}
+			programs, more, err := platform.Programs(context.Background(), 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if more {
+				t.Error("datasets should be read in a single page")
+			}
+
+			var got []string
+			for _, program := range programs {
+				got = append(got, program.Platform+"/"+program.Handle)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("programs = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFilterAssets(t *testing.T) {
+	assets := []types.Asset{
+		{Kind: types.AssetWildcard, Category: "website", Identifier: "*.acme.com", InScope: true},
+		{Kind: types.AssetURL, Category: "website", Identifier: "www.acme.com", InScope: true},
+		{Kind: types.AssetURL, Category: "api", Identifier: "api.acme.com", InScope: true},
+		{Kind: types.AssetMobile, Category: "android", Identifier: "com.acme.app", InScope: true},
+		{Kind: types.AssetURL, Category: "website", Identifier: "blog.acme.com"},
+	}
+
+	tests := []struct {
+		name     string
+		settings types.PlatformConfig
+		want     []string
+	}{
+		{
+			name:     "all",
+			settings: types.PlatformConfig{Scope: "all"},
+			want:     []string{"*.acme.com", "www.acme.com", "api.acme.com", "blog.acme.com"},
+		},
+		{
+			name:     "narrow",
+			settings: types.PlatformConfig{Scope: "narrow"},
+			want:     []string{"www.acme.com", "api.acme.com", "blog.acme.com"},
+		},
+		{
+			name:     "wide",
+			settings: types.PlatformConfig{Scope: "wide"},
+			want:     []string{"*.acme.com", "blog.acme.com"},
+		},
+		{
+			name:     "category",
+			settings: types.PlatformConfig{Scope: "all", Category: "api"},
+			want:     []string{"api.acme.com", "blog.acme.com"},
+		},
+		{
+			name:     "kinds",
+			settings: types.PlatformConfig{Scope: "narrow", Kinds: []string{"mobile", "wildcard"}},
+			want:     []string{"*.acme.com", "com.acme.app", "blog.acme.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, asset := range filterAssets(&test.settings, assets) {
+				got = append(got, asset.Identifier)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(test.want) {
+				t.Errorf("filterAssets = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package platform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,86 +10,96 @@ import (
 	"strings"
 
 	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
 )
 
 const hackerOneBaseURL = "https://api.hackerone.com/v1/hackers/programs"
 
-// HackerOne fetches data from the HackerOne API and processes it based on the configuration.
-func HackerOne(config *types.Config) error {
-	client := &http.Client{}
-	headers := map[string][]string{
+// hackerOneHandleRegex extracts the program handle from a HackerOne URL.
+var hackerOneHandleRegex = regexp.MustCompile(`https://hackerone\.com/([^?]+)`)
+
+// hackerOne scans HackerOne programs through the hacker API.
+type hackerOne struct {
+	config  *types.HackerOneConfig
+	client  *http.Client
+	headers map[string][]string
+}
+
+func init() {
+	Register(func() Platform { return &hackerOne{} })
+}
+
+// Name returns the template key of HackerOne.
+func (h *hackerOne) Name() string {
+	return "hackerone"
+}
+
+// Configure prepares the HTTP client and request headers.
+func (h *hackerOne) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.HackerOne == nil {
+		return nil, nil
+	}
+
+	h.config = config.FindTarget.HackerOne
+	client, err := newHTTPClient(config, &config.FindTarget.HackerOne.PlatformConfig)
+	if err != nil {
+		return nil, err
+	}
+	h.client = client
+	h.headers = map[string][]string{
 		"Accept": {"application/json"},
 	}
+	return &h.config.PlatformConfig, nil
+}
 
+// Programs returns one page of programs, or the include list when set.
+func (h *hackerOne) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
 	// Check if the config has an "Include" array
-	if len(config.FindTarget.HackerOne.Include) > 0 {
-		// Define a regex to extract the handle from the URL
-		handleRegex := regexp.MustCompile(`https://hackerone\.com/([^?]+)`)
-
-		for _, includeURL := range config.FindTarget.HackerOne.Include {
+	if len(h.config.Include) > 0 {
+		var programs []types.Program
+		for _, includeURL := range h.config.Include {
 			// Extract the handle using the regex
-			matches := handleRegex.FindStringSubmatch(includeURL)
+			matches := hackerOneHandleRegex.FindStringSubmatch(includeURL)
 			if len(matches) < 2 {
-				fmt.Printf("Invalid HackerOne URL: %s\n", includeURL)
+				gologger.Warning().Msgf("Invalid HackerOne URL: %s", includeURL)
 				continue
 			}
-			handle := matches[1]
-
-			fmt.Println(handle)
-
-			// Process the program using the extracted handle
-			hasHost, err := processHackerOneProgram(client, handle, headers, config)
-			if err != nil {
-				return err
-			}
-
-			// If MaxPrograms is set, limit the number of processed programs
-			if config.FindTarget.HackerOne.MaxPrograms != 0 && hasHost {
-				return nil
-			}
+			programs = append(programs, types.Program{Handle: matches[1], URL: includeURL})
 		}
-		return nil // Skip the rest of the logic if "Include" is used
+		return programs, false, nil
 	}
 
-	baseURL := hackerOneBaseURL
-	var limit int8 = 0
-
-	for baseURL != "" {
-		// Fetch programs from HackerOne
-		result, nextURL, err := fetchHackerOnePrograms(client, baseURL, headers, config)
-		if err != nil {
-			return err
-		}
-		baseURL = nextURL
-
-		// Process each program
-		for _, program := range result.Data {
-			if config.FindTarget.HackerOne.MaxPrograms != 0 && limit >= config.FindTarget.HackerOne.MaxPrograms {
-				return nil
-			}
-
-			hasHost, err := processHackerOneProgram(client, program.Attributes.Handle, headers, config)
-			if err != nil {
-				return err
-			}
+	pageURL := fmt.Sprintf("%s?page[number]=%d&page[size]=100", hackerOneBaseURL, page)
+	result, nextURL, err := fetchHackerOnePrograms(ctx, h.client, pageURL, h.headers, h.config)
+	if err != nil {
+		return nil, false, err
+	}
 
-			if config.FindTarget.HackerOne.MaxPrograms != 0 && hasHost {
-				limit++
-			}
-		}
+	programs := make([]types.Program, 0, len(result.Data))
+	for _, program := range result.Data {
+		programs = append(programs, types.Program{
+			Handle: program.Attributes.Handle,
+			Name:   program.Attributes.Name,
+			URL:    "https://hackerone.com/" + program.Attributes.Handle,
+		})
 	}
 
-	return nil
+	return programs, nextURL != "", nil
+}
+
+// Scope fetches the structured scopes of a program and returns its assets.
+func (h *hackerOne) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	return processHackerOneProgram(ctx, h.client, program.Handle, h.headers, h.config)
 }
 
 // fetchHackerOnePrograms fetches a list of programs from HackerOne.
-func fetchHackerOnePrograms(client *http.Client, baseURL string, headers map[string][]string, config *types.Config) (types.HackerOneResponse, string, error) {
-	req, err := http.NewRequest("GET", baseURL, nil)
+func fetchHackerOnePrograms(ctx context.Context, client *http.Client, baseURL string, headers map[string][]string, config *types.HackerOneConfig) (types.HackerOneResponse, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL, nil)
 	if err != nil {
 		return types.HackerOneResponse{}, "", fmt.Errorf("error creating request: %v", err)
 	}
-	req.Header = headers
-	req.SetBasicAuth(config.FindTarget.HackerOne.H1Username, config.FindTarget.HackerOne.H1Token)
+	req.Header = http.Header(headers).Clone()
+	req.SetBasicAuth(config.H1Username, config.H1Token)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -114,98 +125,118 @@ func fetchHackerOnePrograms(client *http.Client, baseURL string, headers map[str
 }
 
 // processHackerOneProgram processes a single HackerOne program and its scopes.
-func processHackerOneProgram(client *http.Client, handle string, headers map[string][]string, config *types.Config) (bool, error) {
+func processHackerOneProgram(ctx context.Context, client *http.Client, handle string, headers map[string][]string, config *types.HackerOneConfig) ([]types.Asset, error) {
 	programURL := fmt.Sprintf("%s/%s/structured_scopes?page[size]=100", hackerOneBaseURL, handle)
 
-	req, err := http.NewRequest("GET", programURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", programURL, nil)
 	if err != nil {
-		return false, fmt.Errorf("error creating request: %v", err)
+		return nil, fmt.Errorf("error creating request: %v", err)
 	}
-	req.Header = headers
-	req.SetBasicAuth(config.FindTarget.HackerOne.H1Username, config.FindTarget.HackerOne.H1Token)
+	req.Header = http.Header(headers).Clone()
+	req.SetBasicAuth(config.H1Username, config.H1Token)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return false, fmt.Errorf("error sending request: %v", err)
+		return nil, fmt.Errorf("error sending request: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return false, fmt.Errorf("error reading response body: %v", err)
-	}
-
-	// Check if the response contains "URL" or "WILDCARD"
-	if !strings.Contains(string(body), "URL") && !strings.Contains(string(body), "WILDCARD") {
-		return false, nil
+		return nil, fmt.Errorf("error reading response body: %v", err)
 	}
 
 	var programResult types.H1ProgramStruct
 	if err := json.Unmarshal(body, &programResult); err != nil {
-		return false, fmt.Errorf("error parsing JSON: %v", err)
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
 	}
 
-	return processScopes(programResult.Data, config), nil
+	return processScopes(programResult.Data), nil
 }
 
-// processScopes processes the scopes of a HackerOne program.
-func processScopes(scopes []types.H1ScopeData, config *types.Config) bool {
-	hasHost := false
+// processScopes converts the structured scopes of a HackerOne program into
+// assets.
+func processScopes(scopes []types.H1ScopeData) []types.Asset {
+	var assets []types.Asset
 
 	for _, scopeData := range scopes {
-		assetType := strings.ToLower(scopeData.Attributes.AssetType)
-		assetIdentifier := scopeData.Attributes.AssetIdentifier
-
-		if config.FindTarget.HackerOne.Scope == "wide" && assetType == "wildcard" {
-			hasHost = processWildcardScope(assetIdentifier) || hasHost
-		} else if config.FindTarget.HackerOne.Scope == "narrow" && assetType == "url" {
-			hasHost = processURLScope(assetIdentifier) || hasHost
-		} else if config.FindTarget.HackerOne.Scope == "all" {
-			hasHost = processWildcardScope(assetIdentifier) || hasHost
-		}
+		assets = append(assets, hackerOneAssets(scopeData.Attributes, scopeData.Attributes.EligibleForSubmission)...)
 	}
 
-	return hasHost
+	return assets
 }
 
-// processWildcardScope processes a wildcard scope and prints the host.
-func processWildcardScope(assetIdentifier string) bool {
-	if strings.Contains(assetIdentifier, ",") {
-		hosts := strings.Split(assetIdentifier, ",")
-		host := strings.Replace(hosts[0], "*.", "", 1)
-		fmt.Println(host)
-		return true
-	}
+// hackerOneAssets converts a HackerOne scope into assets. URL and wildcard
+// identifiers listing several hosts are split into one asset each; other
+// identifiers are free text and kept whole.
+func hackerOneAssets(attributes types.H1Attributes, inScope bool) []types.Asset {
+	var assets []types.Asset
+
+	identifiers := []string{attributes.AssetIdentifier}
+	switch strings.ToUpper(attributes.AssetType) {
+	case "URL", "WILDCARD":
+		identifiers = strings.Split(attributes.AssetIdentifier, ",")
+	}
+	for _, identifier := range identifiers {
+		identifier = strings.TrimSpace(identifier)
+		if identifier == "" {
+			continue
+		}
 
-	if strings.Contains(assetIdentifier, "*") {
-		host := strings.Replace(assetIdentifier, "*.", "", 1)
-		fmt.Println(host)
-		return true
+		assets = append(assets, types.Asset{
+			Kind:           hackerOneKind(attributes.AssetType, identifier),
+			Type:           attributes.AssetType,
+			Category:       hackerOneCategory(attributes.AssetType),
+			Identifier:     identifier,
+			InScope:        inScope,
+			BountyEligible: inScope && attributes.EligibleForBounty,
+			MaxSeverity:    attributes.MaxSeverity,
+			Instruction:    attributes.Instruction,
+		})
 	}
 
-	return false
+	return assets
 }
 
-// processURLScope processes a URL scope and prints the host.
-func processURLScope(assetIdentifier string) bool {
-	if strings.Contains(assetIdentifier, ",") {
-		hosts := strings.Split(assetIdentifier, ",")
-		for _, host := range hosts {
-			if !strings.Contains(host, "*") {
-				fmt.Println(host)
-			}
-		}
-		return true
-	}
-
-	if !strings.Contains(assetIdentifier, "*") {
-		fmt.Println(assetIdentifier)
-		return true
-	}
+// hackerOneKind maps a HackerOne asset type to an asset kind.
+func hackerOneKind(assetType string, identifier string) types.AssetKind {
+	switch strings.ToUpper(assetType) {
+	case "URL", "WILDCARD":
+		return classifyHost(identifier)
+	case "CIDR", "IP_ADDRESS":
+		return types.AssetIP
+	case "GOOGLE_PLAY_APP_ID", "APPLE_STORE_APP_ID", "OTHER_APK", "OTHER_IPA", "TESTFLIGHT":
+		return types.AssetMobile
+	case "SOURCE_CODE":
+		return types.AssetSourceCode
+	case "DOWNLOADABLE_EXECUTABLES", "WINDOWS_APP_STORE_APP_ID":
+		return types.AssetExecutable
+	case "HARDWARE":
+		return types.AssetHardware
+	case "SMART_CONTRACT":
+		return types.AssetSmartContract
+	}
+	return types.AssetOther
+}
 
-	return false
+// hackerOneCategory maps a HackerOne asset type to the Bugcrowd style
+// category used by the template category filter.
+func hackerOneCategory(assetType string) string {
+	switch strings.ToUpper(assetType) {
+	case "URL", "WILDCARD":
+		return "website"
+	case "CIDR", "IP_ADDRESS":
+		return "network"
+	case "GOOGLE_PLAY_APP_ID", "OTHER_APK":
+		return "android"
+	case "APPLE_STORE_APP_ID", "OTHER_IPA", "TESTFLIGHT":
+		return "ios"
+	case "HARDWARE":
+		return "hardware"
+	}
+	return "other"
 }
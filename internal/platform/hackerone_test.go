@@ -0,0 +1,35 @@
+package platform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+func TestHackerOneAssetsSplit(t *testing.T) {
+	tests := []struct {
+		assetType  string
+		identifier string
+		want       []string
+	}{
+		{"URL", "api.acme.com, www.acme.com", []string{"api.acme.com", "www.acme.com"}},
+		{"WILDCARD", "*.acme.com,*.acme.net", []string{"*.acme.com", "*.acme.net"}},
+		{"OTHER", "Our apps, APIs and website", []string{"Our apps, APIs and website"}},
+		{"SOURCE_CODE", "github.com/acme/a, github.com/acme/b", []string{"github.com/acme/a, github.com/acme/b"}},
+		{"HARDWARE", "Acme Router, model X", []string{"Acme Router, model X"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.assetType, func(t *testing.T) {
+			attributes := types.H1Attributes{AssetType: test.assetType, AssetIdentifier: test.identifier}
+			var got []string
+			for _, asset := range hackerOneAssets(attributes, true) {
+				got = append(got, asset.Identifier)
+			}
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", test.want) {
+				t.Errorf("identifiers = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
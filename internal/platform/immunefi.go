@@ -0,0 +1,151 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+const immunefiBaseURL = "https://immunefi.com"
+
+// contractAddressRegex matches an EVM contract address inside an explorer URL.
+var contractAddressRegex = regexp.MustCompile(`0x[0-9a-fA-F]{40}`)
+
+// immunefi scans the public Immunefi bounty listing.
+type immunefi struct {
+	config    *types.ImmunefiConfig
+	client    *http.Client
+	include   map[string]bool
+	minReward float64
+}
+
+func init() {
+	Register(func() Platform { return &immunefi{} })
+}
+
+// Name returns the template key of Immunefi.
+func (i *immunefi) Name() string {
+	return "immunefi"
+}
+
+// Configure prepares the HTTP client and the filters.
+func (i *immunefi) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.Immunefi == nil {
+		return nil, nil
+	}
+
+	client, err := newHTTPClient(config, &config.FindTarget.Immunefi.PlatformConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	i.config = config.FindTarget.Immunefi
+	i.client = client
+
+	if i.config.Reward != "" && i.config.Reward != "points" {
+		i.minReward, err = strconv.ParseFloat(i.config.Reward, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reward %q: %v", i.config.Reward, err)
+		}
+	}
+
+	if len(i.config.Include) > 0 {
+		i.include = make(map[string]bool)
+		for _, includeURL := range i.config.Include {
+			i.include[path.Base(strings.TrimSuffix(includeURL, "/"))] = true
+		}
+	}
+
+	return &i.config.PlatformConfig, nil
+}
+
+// Programs returns every bounty of the listing. Immunefi publishes the
+// assets together with the bounties, so they are attached right away.
+func (i *immunefi) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	var bounties []types.ImmunefiBounty
+	if err := getJSON(ctx, i.client, immunefiBaseURL+"/public-api/bounties.json", nil, &bounties); err != nil {
+		return nil, false, fmt.Errorf("failed to fetch Immunefi bounties: %v", err)
+	}
+
+	var programs []types.Program
+	for _, bounty := range bounties {
+		if i.include != nil && !i.include[bounty.Slug] {
+			continue
+		}
+		if !i.matchReward(bounty) {
+			continue
+		}
+
+		program := types.Program{
+			ID:     bounty.ID,
+			Handle: bounty.Slug,
+			Name:   bounty.Project,
+			URL:    immunefiBaseURL + "/bug-bounty/" + bounty.Slug + "/",
+		}
+		for _, asset := range bounty.Assets {
+			program.Assets = append(program.Assets, immunefiAsset(asset, bounty.MaxBounty > 0))
+		}
+		programs = append(programs, program)
+	}
+
+	return programs, false, nil
+}
+
+// matchReward applies the reward filter of the template, which holds the
+// minimum max-bounty for Immunefi.
+func (i *immunefi) matchReward(bounty types.ImmunefiBounty) bool {
+	switch {
+	case i.config.Reward == "":
+		return true
+	case i.config.Reward == "points":
+		return bounty.MaxBounty == 0
+	default:
+		return bounty.MaxBounty >= i.minReward
+	}
+}
+
+// Scope returns the assets attached to the bounty by Programs.
+func (i *immunefi) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	return program.Assets, nil
+}
+
+// immunefiAsset converts an Immunefi asset, telling websites, smart contract
+// addresses and source repositories apart.
+func immunefiAsset(asset types.ImmunefiAsset, bounty bool) types.Asset {
+	result := types.Asset{
+		Kind:           types.AssetOther,
+		Type:           asset.Type,
+		Category:       "other",
+		Identifier:     asset.URL,
+		InScope:        true,
+		BountyEligible: bounty,
+		Instruction:    asset.Description,
+	}
+
+	host := ""
+	if parsedURL, err := url.Parse(asset.URL); err == nil {
+		host = strings.TrimPrefix(parsedURL.Hostname(), "www.")
+	}
+
+	switch {
+	case host == "github.com" || host == "gitlab.com":
+		result.Kind = types.AssetSourceCode
+	case asset.Type == "smart_contract":
+		result.Kind = types.AssetSmartContract
+		if address := contractAddressRegex.FindString(asset.URL); address != "" {
+			result.Identifier = address
+		}
+	case asset.Type == "websites_and_applications":
+		result.Kind = classifyHost(asset.URL)
+		result.Category = "website"
+	}
+
+	return result
+}
@@ -0,0 +1,181 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// intigritiPageSize is the number of programs requested per listing page.
+const intigritiPageSize = 50
+
+// intigritiHandleRegex extracts the program handle from an Intigriti URL.
+var intigritiHandleRegex = regexp.MustCompile(`programs/[^/]+/([^/?]+)`)
+
+// intigriti scans Intigriti programs through the researcher API.
+type intigriti struct {
+	config    *types.IntigritiConfig
+	client    *http.Client
+	headers   http.Header
+	include   map[string]bool
+	minReward float64
+}
+
+func init() {
+	Register(func() Platform { return &intigriti{} })
+}
+
+// Name returns the template key of Intigriti.
+func (i *intigriti) Name() string {
+	return "intigriti"
+}
+
+// Configure prepares the HTTP client, the API token and the filters.
+func (i *intigriti) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.Intigriti == nil {
+		return nil, nil
+	}
+
+	client, err := newHTTPClient(config, &config.FindTarget.Intigriti.PlatformConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	i.config = config.FindTarget.Intigriti
+	i.client = client
+	i.headers = http.Header{"Accept": {"application/json"}}
+	if i.config.Token != "" {
+		i.headers.Set("Authorization", "Bearer "+i.config.Token)
+	}
+
+	if i.config.Reward != "" && i.config.Reward != "points" {
+		i.minReward, err = strconv.ParseFloat(i.config.Reward, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reward %q: %v", i.config.Reward, err)
+		}
+	}
+
+	// The researcher API addresses programs by ID, so included programs are
+	// picked from the listing by handle.
+	if len(i.config.Include) > 0 {
+		i.include = make(map[string]bool)
+		for _, includeURL := range i.config.Include {
+			handle := includeURL
+			if matches := intigritiHandleRegex.FindStringSubmatch(includeURL); len(matches) == 2 {
+				handle = matches[1]
+			}
+			i.include[strings.ToLower(handle)] = true
+		}
+	}
+
+	return &i.config.PlatformConfig, nil
+}
+
+// Programs returns one page of programs matching the reward and include filters.
+func (i *intigriti) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	offset := (page - 1) * intigritiPageSize
+	pageURL := fmt.Sprintf("%s/programs?limit=%d&offset=%d", i.config.APIURL, intigritiPageSize, offset)
+
+	var data types.IntigritiResponse
+	if err := getJSON(ctx, i.client, pageURL, i.headers, &data); err != nil {
+		return nil, false, fmt.Errorf("failed to fetch Intigriti programs: %v", err)
+	}
+
+	var programs []types.Program
+	for _, program := range data.Records {
+		if i.include != nil && !i.include[strings.ToLower(program.Handle)] {
+			continue
+		}
+		if !i.matchReward(program) {
+			continue
+		}
+		programs = append(programs, types.Program{
+			ID:     program.ID,
+			Handle: program.Handle,
+			Name:   program.Name,
+			URL:    program.WebLinks.Detail,
+		})
+	}
+
+	return programs, offset+len(data.Records) < data.MaxCount, nil
+}
+
+// matchReward applies the reward filter of the template to a program.
+func (i *intigriti) matchReward(program types.IntigritiProgram) bool {
+	switch {
+	case i.config.Reward == "":
+		return true
+	case i.config.Reward == "points":
+		return program.MaxBounty.Value == 0
+	default:
+		return program.MaxBounty.Value >= i.minReward
+	}
+}
+
+// Scope fetches the program details and returns its domains.
+func (i *intigriti) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	var details types.IntigritiProgram
+	if err := getJSON(ctx, i.client, i.config.APIURL+"/programs/"+program.ID, i.headers, &details); err != nil {
+		return nil, fmt.Errorf("failed to fetch Intigriti program: %v", err)
+	}
+	if details.Domains == nil {
+		return nil, nil
+	}
+
+	var assets []types.Asset
+	for _, domain := range details.Domains.Content {
+		identifier := extractURL(strings.TrimSpace(domain.Endpoint))
+		tier := strings.ToLower(domain.Tier.Value)
+		inScope := tier != "out of scope"
+
+		assets = append(assets, types.Asset{
+			Kind:           intigritiKind(domain.Type.Value, identifier),
+			Type:           domain.Type.Value,
+			Category:       intigritiCategory(domain.Type.Value),
+			Identifier:     identifier,
+			InScope:        inScope,
+			BountyEligible: inScope && tier != "no bounty" && details.MaxBounty.Value > 0,
+			Instruction:    domain.Description,
+		})
+	}
+
+	return assets, nil
+}
+
+// intigritiKind maps an Intigriti domain type to an asset kind.
+func intigritiKind(domainType string, identifier string) types.AssetKind {
+	switch strings.ToLower(domainType) {
+	case "url", "wildcard":
+		return classifyHost(identifier)
+	case "iprange":
+		return types.AssetIP
+	case "android", "ios":
+		return types.AssetMobile
+	case "device":
+		return types.AssetHardware
+	}
+	return types.AssetOther
+}
+
+// intigritiCategory maps an Intigriti domain type to the Bugcrowd style
+// category used by the template category filter.
+func intigritiCategory(domainType string) string {
+	switch strings.ToLower(domainType) {
+	case "url", "wildcard":
+		return "website"
+	case "iprange":
+		return "network"
+	case "android":
+		return "android"
+	case "ios":
+		return "ios"
+	case "device":
+		return "hardware"
+	}
+	return "other"
+}
@@ -0,0 +1,187 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// intigritiPrograms is the number of programs listed by the test server,
+// enough to span two listing pages.
+const intigritiPrograms = 60
+
+// newIntigritiServer serves a program listing where program N is named
+// "progN" and offers a max bounty of N*10, and the details of program "7".
+func newIntigritiServer(t *testing.T, offsets *[]int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/programs", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		*offsets = append(*offsets, offset)
+
+		page := types.IntigritiResponse{MaxCount: intigritiPrograms}
+		for n := offset; n < offset+limit && n < intigritiPrograms; n++ {
+			program := types.IntigritiProgram{
+				ID:        strconv.Itoa(n),
+				Handle:    fmt.Sprintf("prog%d", n),
+				Name:      fmt.Sprintf("Program %d", n),
+				MaxBounty: types.IntigritiAmount{Value: float64(n * 10), Currency: "EUR"},
+			}
+			program.WebLinks.Detail = "https://app.intigriti.com/programs/acme/" + program.Handle + "/detail"
+			page.Records = append(page.Records, program)
+		}
+		json.NewEncoder(w).Encode(page)
+	})
+	mux.HandleFunc("/programs/7", func(w http.ResponseWriter, r *http.Request) {
+		domain := func(domainType, endpoint, tier string) types.IntigritiDomain {
+			return types.IntigritiDomain{
+				Type:     types.IntigritiValue{Value: domainType},
+				Endpoint: endpoint,
+				Tier:     types.IntigritiValue{Value: tier},
+			}
+		}
+		json.NewEncoder(w).Encode(types.IntigritiProgram{
+			ID:        "7",
+			MaxBounty: types.IntigritiAmount{Value: 70},
+			Domains: &types.IntigritiDomains{Content: []types.IntigritiDomain{
+				domain("Url", "api.acme.com", "Tier 1"),
+				domain("Wildcard", "*.acme.com (all subdomains)", "Tier 2"),
+				domain("IpRange", "10.0.0.0/24", "No Bounty"),
+				domain("Android", "com.acme.app", "Tier 3"),
+				domain("iOS", "id123456", "Tier 3"),
+				domain("Device", "acme-router", "Tier 1"),
+				domain("Other", "acme-desktop", "Tier 1"),
+				domain("Url", "blog.acme.com", "Out Of Scope"),
+			}},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+// newTestIntigriti configures an Intigriti platform against the test server.
+func newTestIntigriti(t *testing.T, apiURL string, settings types.PlatformConfig) *intigriti {
+	config := &types.Config{}
+	config.FindTarget.Intigriti = &types.IntigritiConfig{PlatformConfig: settings, Token: "secret", APIURL: apiURL}
+	config.SetDefaults()
+
+	platform := &intigriti{}
+	if _, err := platform.Configure(config); err != nil {
+		t.Fatal(err)
+	}
+	return platform
+}
+
+// listIntigriti returns the handles of every program listed by the platform.
+func listIntigriti(t *testing.T, platform *intigriti) []string {
+	var handles []string
+	for page := 1; ; page++ {
+		programs, more, err := platform.Programs(context.Background(), page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, program := range programs {
+			handles = append(handles, program.Handle)
+		}
+		if !more {
+			return handles
+		}
+	}
+}
+
+func TestIntigritiProgramsPagination(t *testing.T) {
+	var offsets []int
+	server := newIntigritiServer(t, &offsets)
+	defer server.Close()
+
+	handles := listIntigriti(t, newTestIntigriti(t, server.URL, types.PlatformConfig{}))
+	if len(handles) != intigritiPrograms {
+		t.Fatalf("listed %d programs, want %d", len(handles), intigritiPrograms)
+	}
+	if handles[0] != "prog0" || handles[intigritiPrograms-1] != "prog59" {
+		t.Errorf("unexpected programs %s ... %s", handles[0], handles[intigritiPrograms-1])
+	}
+	if fmt.Sprint(offsets) != fmt.Sprint([]int{0, intigritiPageSize}) {
+		t.Errorf("requested offsets %v", offsets)
+	}
+}
+
+func TestIntigritiProgramsFilters(t *testing.T) {
+	var offsets []int
+	server := newIntigritiServer(t, &offsets)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		settings types.PlatformConfig
+		want     []string
+	}{
+		{
+			name: "include",
+			settings: types.PlatformConfig{Include: []string{
+				"https://app.intigriti.com/programs/acme/prog3/detail",
+				"PROG55",
+			}},
+			want: []string{"prog3", "prog55"},
+		},
+		{
+			name:     "reward",
+			settings: types.PlatformConfig{Reward: "570"},
+			want:     []string{"prog57", "prog58", "prog59"},
+		},
+		{
+			name:     "points",
+			settings: types.PlatformConfig{Reward: "points"},
+			want:     []string{"prog0"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handles := listIntigriti(t, newTestIntigriti(t, server.URL, test.settings))
+			if fmt.Sprint(handles) != fmt.Sprint(test.want) {
+				t.Errorf("listed %v, want %v", handles, test.want)
+			}
+		})
+	}
+}
+
+func TestIntigritiScope(t *testing.T) {
+	var offsets []int
+	server := newIntigritiServer(t, &offsets)
+	defer server.Close()
+
+	platform := newTestIntigriti(t, server.URL, types.PlatformConfig{})
+	assets, err := platform.Scope(context.Background(), types.Program{ID: "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []types.Asset{
+		{Kind: types.AssetURL, Category: "website", Identifier: "api.acme.com", InScope: true, BountyEligible: true},
+		{Kind: types.AssetWildcard, Category: "website", Identifier: "*.acme.com", InScope: true, BountyEligible: true},
+		{Kind: types.AssetIP, Category: "network", Identifier: "10.0.0.0/24", InScope: true},
+		{Kind: types.AssetMobile, Category: "android", Identifier: "com.acme.app", InScope: true, BountyEligible: true},
+		{Kind: types.AssetMobile, Category: "ios", Identifier: "id123456", InScope: true, BountyEligible: true},
+		{Kind: types.AssetHardware, Category: "hardware", Identifier: "acme-router", InScope: true, BountyEligible: true},
+		{Kind: types.AssetOther, Category: "other", Identifier: "acme-desktop", InScope: true, BountyEligible: true},
+		{Kind: types.AssetURL, Category: "website", Identifier: "blog.acme.com"},
+	}
+	if len(assets) != len(want) {
+		t.Fatalf("got %d assets, want %d", len(assets), len(want))
+	}
+	for i, asset := range assets {
+		w := want[i]
+		if asset.Kind != w.Kind || asset.Category != w.Category || asset.Identifier != w.Identifier ||
+			asset.InScope != w.InScope || asset.BountyEligible != w.BountyEligible {
+			t.Errorf("asset %d = %+v, want %+v", i, asset, w)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"golang.org/x/net/html"
+)
+
+const openBugBountyBaseURL = "https://www.openbugbounty.org"
+
+var (
+	// openBugBountyProgramRegex matches the program links of the listing.
+	openBugBountyProgramRegex = regexp.MustCompile(`^/bugbounty/([^/]+)/?$`)
+	// domainRegex matches a domain name, optionally prefixed with a wildcard.
+	domainRegex = regexp.MustCompile(`^(\*\.)?([a-zA-Z0-9-]+\.)+[a-zA-Z]{2,}$`)
+)
+
+// openBugBounty scans the Open Bug Bounty program listing. Every program on
+// the platform is a VDP, so it only matches the "points" reward filter.
+type openBugBounty struct {
+	config *types.OpenBugBountyConfig
+	client *http.Client
+}
+
+func init() {
+	Register(func() Platform { return &openBugBounty{} })
+}
+
+// Name returns the template key of Open Bug Bounty.
+func (o *openBugBounty) Name() string {
+	return "openbugbounty"
+}
+
+// Configure prepares the HTTP client. A reward amount disables the platform
+// since its programs offer no monetary rewards.
+func (o *openBugBounty) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.OpenBugBounty == nil {
+		return nil, nil
+	}
+
+	o.config = config.FindTarget.OpenBugBounty
+	if o.config.Reward != "" && o.config.Reward != "points" {
+		return nil, nil
+	}
+
+	client, err := newHTTPClient(config, &config.FindTarget.OpenBugBounty.PlatformConfig)
+	if err != nil {
+		return nil, err
+	}
+	o.client = client
+
+	return &o.config.PlatformConfig, nil
+}
+
+// Programs returns the programs of the listing, or the include list when set.
+func (o *openBugBounty) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	// Check if the config has an "Include" array
+	if len(o.config.Include) > 0 {
+		programs := make([]types.Program, 0, len(o.config.Include))
+		for _, includeURL := range o.config.Include {
+			handle := path.Base(strings.TrimSuffix(includeURL, "/"))
+			programs = append(programs, types.Program{Handle: handle, Name: handle, URL: includeURL})
+		}
+		return programs, false, nil
+	}
+
+	doc, err := fetchHTML(ctx, o.client, openBugBountyBaseURL+"/bugbounty-list/")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch Open Bug Bounty programs: %v", err)
+	}
+
+	var programs []types.Program
+	seen := make(map[string]bool)
+	walkHTML(doc, func(node *html.Node) {
+		if node.Type != html.ElementNode || node.Data != "a" {
+			return
+		}
+		matches := openBugBountyProgramRegex.FindStringSubmatch(attribute(node, "href"))
+		if len(matches) < 2 || seen[matches[1]] {
+			return
+		}
+		seen[matches[1]] = true
+		programs = append(programs, types.Program{
+			Handle: matches[1],
+			Name:   strings.TrimSpace(textContent(node)),
+			URL:    openBugBountyBaseURL + "/bugbounty/" + matches[1] + "/",
+		})
+	})
+
+	return programs, false, nil
+}
+
+// Scope fetches the program page and returns the domains listed in its tables.
+func (o *openBugBounty) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	doc, err := fetchHTML(ctx, o.client, program.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch program page: %v", err)
+	}
+
+	var assets []types.Asset
+	seen := make(map[string]bool)
+	walkHTML(doc, func(node *html.Node) {
+		if node.Type != html.ElementNode || node.Data != "td" {
+			return
+		}
+		domain := strings.ToLower(strings.TrimSpace(textContent(node)))
+		if !domainRegex.MatchString(domain) || seen[domain] {
+			return
+		}
+		seen[domain] = true
+		assets = append(assets, types.Asset{
+			Kind:       classifyHost(domain),
+			Type:       "domain",
+			Category:   "website",
+			Identifier: domain,
+			InScope:    true,
+		})
+	})
+
+	return assets, nil
+}
+
+// fetchHTML fetches a page and parses it into an HTML tree.
+func fetchHTML(ctx context.Context, client *http.Client, pageURL string) (*html.Node, error) {
+	resp, err := get(ctx, client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	return html.Parse(resp.Body)
+}
+
+// walkHTML calls fn for every node of the tree in document order.
+func walkHTML(node *html.Node, fn func(*html.Node)) {
+	fn(node)
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		walkHTML(child, fn)
+	}
+}
+
+// attribute returns the value of an attribute of a node.
+func attribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// textContent returns the concatenated text of a node and its children.
+func textContent(node *html.Node) string {
+	var text strings.Builder
+	walkHTML(node, func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+		}
+	})
+	return text.String()
+}
@@ -0,0 +1,356 @@
+package platform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/internal/httpclient"
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
+)
+
+// Platform is implemented by every bug bounty platform findtarget can scan.
+type Platform interface {
+	// Name returns the key of the platform section in the template.
+	Name() string
+	// Configure loads the platform section from the template and returns its
+	// shared settings, or nil when the template does not enable the platform.
+	Configure(config *types.Config) (*types.PlatformConfig, error)
+	// Programs returns the programs on the given page, starting at 1, and
+	// whether another page follows.
+	Programs(ctx context.Context, page int) ([]types.Program, bool, error)
+	// Scope fetches every in-scope and out-of-scope asset of a program.
+	// Filtering by scope mode and category is left to the caller. Scope may
+	// be called concurrently, also with Programs.
+	Scope(ctx context.Context, program types.Program) ([]types.Asset, error)
+}
+
+var registry []func() Platform
+
+// Register adds a platform to the registry. Platforms call it from init so
+// they are picked up without changes to the command line tool.
+func Register(factory func() Platform) {
+	registry = append(registry, factory)
+}
+
+// Platforms returns a fresh instance of every registered platform.
+func Platforms() []Platform {
+	platforms := make([]Platform, 0, len(registry))
+	for _, factory := range registry {
+		platforms = append(platforms, factory())
+	}
+	return platforms
+}
+
+// Run scans every platform enabled in the template and passes the programs
+// with in-scope targets to emit. The platforms are scanned in parallel with up
+// to concurrency requests in flight, but the programs are emitted in the
+// order of a sequential scan: platform by platform, in listing order. A
+// platform whose listing fails is logged and skipped, and Run returns an error
+// naming the failed platforms once the others were scanned. When ctx
+// is done, the requests in flight are cancelled and Run returns the context
+// error once the programs completed before were emitted.
+func Run(ctx context.Context, config *types.Config, concurrency int, emit func(types.Program) error) error {
+	type enabled struct {
+		platform Platform
+		settings *types.PlatformConfig
+	}
+
+	var platforms []enabled
+	for _, p := range Platforms() {
+		settings, err := p.Configure(config)
+		if err != nil {
+			return fmt.Errorf("%s: %v", p.Name(), err)
+		}
+		if settings != nil {
+			platforms = append(platforms, enabled{platform: p, settings: settings})
+		}
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	workers := make(chan struct{}, concurrency)
+	// Cancelling stops the scans still running when Run returns
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outputs := make([]chan types.Program, len(platforms))
+	errs := make([]chan error, len(platforms))
+	for i, e := range platforms {
+		outputs[i] = make(chan types.Program)
+		errs[i] = make(chan error, 1)
+		go func(p Platform, settings *types.PlatformConfig, out chan types.Program, errc chan error) {
+			defer close(out)
+			errc <- scan(ctx, p, settings, workers, func(program types.Program) error {
+				select {
+				case out <- program:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		}(e.platform, e.settings, outputs[i], errs[i])
+	}
+
+	// Platforms whose listing failed; the others are still scanned
+	var failed []string
+	for i, e := range platforms {
+		for program := range outputs[i] {
+			if err := emit(program); err != nil {
+				return err
+			}
+		}
+		if err := <-errs[i]; err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			gologger.Error().Msgf("%s: %v", e.platform.Name(), err)
+			failed = append(failed, e.platform.Name())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to scan %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// scopeJob is a program whose scope is being fetched, or the error that
+// ended the program listing.
+type scopeJob struct {
+	program types.Program
+	result  chan scopeResult
+	err     error
+}
+
+type scopeResult struct {
+	assets []types.Asset
+	err    error
+}
+
+// scan walks the program pages of a platform and emits every program with
+// in-scope targets until MaxPrograms of them have been found. The scopes are
+// fetched ahead by the shared workers and handled in listing order, so the
+// programs emitted do not depend on the concurrency.
+func scan(ctx context.Context, p Platform, settings *types.PlatformConfig, workers chan struct{}, emit func(types.Program) error) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	jobs := make(chan scopeJob, cap(workers))
+	go listPrograms(ctx, p, workers, done, jobs)
+
+	found := 0
+
+	// Programs whose scope could not be fetched, even after retries
+	var failed []string
+	defer func() {
+		if len(failed) > 0 {
+			gologger.Error().Msgf("%s: failed to fetch the scope of %d programs: %s", p.Name(), len(failed), strings.Join(failed, ", "))
+		}
+	}()
+
+	for job := range jobs {
+		if job.err != nil {
+			return job.err
+		}
+
+		program := job.program
+		result := <-job.result
+		if ctx.Err() != nil {
+			// Requests cancelled by the interruption are not failures
+			return ctx.Err()
+		}
+		if result.err != nil {
+			gologger.Warning().Msgf("Failed to fetch scope for %s: %v", program.URL, result.err)
+			failed = append(failed, program.URL)
+			continue
+		}
+
+		if program.Platform == "" {
+			program.Platform = p.Name()
+		}
+		program.Assets = filterAssets(settings, result.assets)
+
+		if len(program.InScopeAssets()) == 0 {
+			continue
+		}
+		if err := emit(program); err != nil {
+			return err
+		}
+		found++
+		if settings.MaxPrograms != 0 && found >= settings.MaxPrograms {
+			return nil
+		}
+	}
+	return nil
+}
+
+// listPrograms walks the program pages and starts fetching the scope of every
+// program as soon as a worker is free, queueing the jobs in listing order. It
+// returns once the listing ends or fails, or when the scan is done or cancelled.
+func listPrograms(ctx context.Context, p Platform, workers chan struct{}, done chan struct{}, jobs chan<- scopeJob) {
+	defer close(jobs)
+
+	acquire := func() bool {
+		select {
+		case workers <- struct{}{}:
+			return true
+		case <-ctx.Done():
+		case <-done:
+		}
+		return false
+	}
+	queue := func(job scopeJob) bool {
+		select {
+		case jobs <- job:
+			return true
+		case <-ctx.Done():
+		case <-done:
+		}
+		return false
+	}
+
+	for page := 1; ; page++ {
+		if !acquire() {
+			return
+		}
+		programs, more, err := p.Programs(ctx, page)
+		<-workers
+		if err != nil {
+			queue(scopeJob{err: err})
+			return
+		}
+
+		for _, program := range programs {
+			if !acquire() {
+				return
+			}
+			result := make(chan scopeResult, 1)
+			go func(program types.Program) {
+				assets, err := p.Scope(ctx, program)
+				<-workers
+				result <- scopeResult{assets: assets, err: err}
+			}(program)
+
+			if !queue(scopeJob{program: program, result: result}) {
+				return
+			}
+		}
+
+		if !more {
+			return
+		}
+	}
+}
+
+// filterAssets drops the in-scope assets that do not match the configured
+// category and scope mode, or the asset kinds when the template lists them.
+// Out-of-scope assets are kept as exclusions.
+func filterAssets(settings *types.PlatformConfig, assets []types.Asset) []types.Asset {
+	var filtered []types.Asset
+	for _, asset := range assets {
+		if asset.InScope {
+			if settings.Category != "" && settings.Category != asset.Category {
+				continue
+			}
+			if len(settings.Kinds) > 0 {
+				if !matchKinds(settings.Kinds, asset.Kind) {
+					continue
+				}
+			} else if !matchScope(settings.Scope, asset.Kind) {
+				continue
+			}
+		}
+		filtered = append(filtered, asset)
+	}
+	return filtered
+}
+
+// matchScope reports whether an asset kind belongs to a scope mode. The
+// narrow scope selects URLs, the wide scope selects wildcards and all
+// selects both.
+func matchScope(scope string, kind types.AssetKind) bool {
+	switch scope {
+	case "narrow":
+		return kind == types.AssetURL
+	case "wide":
+		return kind == types.AssetWildcard
+	case "all":
+		return kind == types.AssetURL || kind == types.AssetWildcard
+	}
+	return false
+}
+
+// matchKinds reports whether an asset kind is one of the listed kinds.
+func matchKinds(kinds []string, kind types.AssetKind) bool {
+	for _, k := range kinds {
+		if types.AssetKind(k) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyHost returns the kind of a host or URL identifier. Only a leading
+// "*." label makes a wildcard; any other "*" cannot be targeted directly.
+func classifyHost(identifier string) types.AssetKind {
+	if strings.HasPrefix(identifier, "*.") && !strings.Contains(strings.TrimPrefix(identifier, "*."), "*") {
+		return types.AssetWildcard
+	}
+	if strings.Contains(identifier, "*") {
+		return types.AssetOther
+	}
+	return types.AssetURL
+}
+
+// newHTTPClient creates the HTTP client of a platform from the network and
+// cache settings of the template and the rate limit of the platform.
+func newHTTPClient(config *types.Config, settings *types.PlatformConfig) (*http.Client, error) {
+	return httpclient.New(httpclient.Options{
+		Proxies:   config.Proxy,
+		Timeout:   config.Timeout,
+		Headers:   config.Headers,
+		RateLimit: settings.RateLimit,
+		Retries:   config.Retries,
+		CacheDir:  config.CacheDir,
+		CacheTTL:  config.CacheTTL,
+	})
+}
+
+// get sends a GET request bound to ctx.
+func get(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// getJSON fetches a URL with the given headers and decodes its JSON body into v.
+func getJSON(ctx context.Context, client *http.Client, url string, headers http.Header, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	if headers != nil {
+		req.Header = headers.Clone()
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+const yesWeHackBaseURL = "https://api.yeswehack.com"
+
+// yesWeHack scans public YesWeHack programs.
+type yesWeHack struct {
+	config    *types.YesWeHackConfig
+	client    *http.Client
+	minReward int
+}
+
+func init() {
+	Register(func() Platform { return &yesWeHack{} })
+}
+
+// Name returns the template key of YesWeHack.
+func (y *yesWeHack) Name() string {
+	return "yeswehack"
+}
+
+// Configure prepares the HTTP client and the reward filter.
+func (y *yesWeHack) Configure(config *types.Config) (*types.PlatformConfig, error) {
+	if config.FindTarget.YesWeHack == nil {
+		return nil, nil
+	}
+
+	client, err := newHTTPClient(config, &config.FindTarget.YesWeHack.PlatformConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	y.config = config.FindTarget.YesWeHack
+	y.client = client
+
+	if y.config.Reward != "" && y.config.Reward != "points" {
+		y.minReward, err = strconv.Atoi(y.config.Reward)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reward %q: %v", y.config.Reward, err)
+		}
+	}
+
+	return &y.config.PlatformConfig, nil
+}
+
+// Programs returns one page of public programs, or the include list when set.
+func (y *yesWeHack) Programs(ctx context.Context, page int) ([]types.Program, bool, error) {
+	// Check if the config has an "Include" array
+	if len(y.config.Include) > 0 {
+		programs := make([]types.Program, 0, len(y.config.Include))
+		for _, includeURL := range y.config.Include {
+			programs = append(programs, types.Program{Handle: path.Base(includeURL), URL: includeURL})
+		}
+		return programs, false, nil
+	}
+
+	var data types.YesWeHackResponse
+	pageURL := fmt.Sprintf("%s/programs?page=%d&resultsPerPage=50", yesWeHackBaseURL, page)
+	if err := getJSON(ctx, y.client, pageURL, nil, &data); err != nil {
+		return nil, false, fmt.Errorf("failed to fetch YesWeHack programs: %v", err)
+	}
+
+	var programs []types.Program
+	for _, program := range data.Items {
+		if program.Disabled || !y.matchReward(program) {
+			continue
+		}
+		programs = append(programs, types.Program{
+			Handle: program.Slug,
+			Name:   program.Title,
+			URL:    "https://yeswehack.com/programs/" + program.Slug,
+		})
+	}
+
+	return programs, page < data.Pagination.NbPages, nil
+}
+
+// matchReward applies the reward filter of the template to a program.
+func (y *yesWeHack) matchReward(program types.YesWeHackProgram) bool {
+	switch {
+	case y.config.Reward == "":
+		return true
+	case y.config.Reward == "points":
+		return !program.Bounty
+	default:
+		return program.Bounty && program.BountyRewardMax >= y.minReward
+	}
+}
+
+// Scope fetches the program details and returns its scopes.
+func (y *yesWeHack) Scope(ctx context.Context, program types.Program) ([]types.Asset, error) {
+	var details types.YesWeHackProgram
+	if err := getJSON(ctx, y.client, yesWeHackBaseURL+"/programs/"+program.Handle, nil, &details); err != nil {
+		return nil, fmt.Errorf("failed to fetch YesWeHack program: %v", err)
+	}
+
+	var assets []types.Asset
+	for _, scope := range details.Scopes {
+		identifier := extractURL(strings.TrimSpace(scope.Scope))
+		assets = append(assets, types.Asset{
+			Kind:           yesWeHackKind(scope.ScopeType, identifier),
+			Type:           scope.ScopeType,
+			Category:       yesWeHackCategory(scope.ScopeType),
+			Identifier:     identifier,
+			InScope:        true,
+			BountyEligible: details.Bounty,
+		})
+	}
+
+	for _, outOfScope := range details.OutOfScope {
+		identifier := extractURL(strings.TrimSpace(outOfScope))
+		assets = append(assets, types.Asset{
+			Kind:       classifyHost(identifier),
+			Identifier: identifier,
+		})
+	}
+
+	return assets, nil
+}
+
+// yesWeHackKind maps a YesWeHack scope type to an asset kind.
+func yesWeHackKind(scopeType string, identifier string) types.AssetKind {
+	switch {
+	case scopeType == "web-application" || scopeType == "api":
+		return classifyHost(identifier)
+	case scopeType == "ip-address":
+		return types.AssetIP
+	case strings.HasPrefix(scopeType, "mobile-application"):
+		return types.AssetMobile
+	case scopeType == "application":
+		return types.AssetExecutable
+	}
+	return types.AssetOther
+}
+
+// yesWeHackCategory maps a YesWeHack scope type to the Bugcrowd style
+// category used by the template category filter.
+func yesWeHackCategory(scopeType string) string {
+	switch scopeType {
+	case "web-application":
+		return "website"
+	case "api":
+		return "api"
+	case "mobile-application-android":
+		return "android"
+	case "mobile-application-ios":
+		return "ios"
+	case "ip-address":
+		return "network"
+	}
+	return "other"
+}
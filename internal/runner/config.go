@@ -30,6 +30,20 @@ func LoadTemplate(templatePath string) (*types.Config, error) {
 	if err := yaml.Unmarshal(configData, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse YAML: %v", err)
 	}
+	config.SetDefaults()
 
 	return &config, nil
 }
+
+// ApplyOptions overrides the template settings given on the command line.
+func ApplyOptions(config *types.Config, options *types.Options) {
+	if len(options.Proxy) > 0 {
+		config.Proxy = options.Proxy
+	}
+	if options.CacheDir != "" {
+		config.CacheDir = options.CacheDir
+	}
+	if options.CacheTTL != 0 {
+		config.CacheTTL = options.CacheTTL
+	}
+}
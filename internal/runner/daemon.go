@@ -0,0 +1,202 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"github.com/e1l1ya/findtarget/internal/notify"
+	"github.com/e1l1ya/findtarget/internal/schedule"
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
+)
+
+// job is a template run by the daemon on its own schedule.
+type job struct {
+	path      string
+	config    *types.Config
+	schedule  *schedule.Schedule
+	notifiers []notify.Notifier
+	next      time.Time
+}
+
+// Daemon runs every template on the cron expression of its schedule key and
+// writes the new programs and assets like watch mode. The last run of every
+// template is kept in the state file: a template that never ran, or missed a
+// run while the daemon was stopped, runs right away. The state file is only
+// opened while a run reads or records it, so daemons, watch mode and history
+// queries can share it; a template another daemon already ran for the current
+// slot is skipped. Once ctx is cancelled the daemon returns, writing what the
+// current run found first.
+func Daemon(ctx context.Context, options *types.Options) error {
+	if len(options.Templates) == 0 {
+		return fmt.Errorf("no template provided")
+	}
+	if err := checkStreamable(options); err != nil {
+		return err
+	}
+
+	outputTemplates := make(map[string]string)
+	jobs := make([]*job, 0, len(options.Templates))
+	for _, path := range options.Templates {
+		j, err := newJob(path, options)
+		if err != nil {
+			return err
+		}
+		for name, text := range j.config.OutputTemplates {
+			outputTemplates[name] = text
+		}
+		jobs = append(jobs, j)
+	}
+
+	statePath, err := statePath(options)
+	if err != nil {
+		return err
+	}
+
+	writer, err := newWriter(&types.Config{OutputTemplates: outputTemplates}, options)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	dirWriter, err := newDirWriter(options)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, j := range jobs {
+		last, err := lastRun(statePath, j.path)
+		if err != nil {
+			return err
+		}
+		j.next = now
+		if !last.IsZero() {
+			next, err := j.nextRun(last)
+			if err != nil {
+				return err
+			}
+			if next.After(now) {
+				j.next = next
+			}
+		}
+	}
+
+	for {
+		current := jobs[0]
+		for _, j := range jobs[1:] {
+			if j.next.Before(current.next) {
+				current = j
+			}
+		}
+
+		if wait := time.Until(current.next); wait > 0 {
+			gologger.Info().Msgf("Next run of %s at %s", current.path, current.next.Format(time.RFC3339))
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+
+		// Another daemon sharing the state file may have run the template
+		last, err := lastRun(statePath, current.path)
+		if err != nil {
+			gologger.Warning().Msgf("%v", err)
+		} else if next := current.schedule.Next(last); !last.IsZero() && next.After(time.Now()) {
+			gologger.Info().Msgf("%s already ran at %s", current.path, last.Format(time.RFC3339))
+			if current.next, err = current.nextRun(last); err != nil {
+				return err
+			}
+			continue
+		}
+
+		started := time.Now()
+		gologger.Info().Msgf("Running %s", current.path)
+		err = watchCycle(ctx, current.path, current.config, options, statePath, current.notifiers, writer, dirWriter)
+		if ctx.Err() != nil {
+			// The interrupted run is not recorded so it runs again on restart
+			return nil
+		}
+		if err != nil {
+			gologger.Error().Msgf("Run of %s failed: %v", current.path, err)
+		}
+		if err := setLastRun(statePath, current.path, started); err != nil {
+			gologger.Warning().Msgf("%v", err)
+		}
+		if current.next, err = current.nextRun(started); err != nil {
+			return err
+		}
+	}
+}
+
+// lastRun reads the last run of a template from the state file, which is
+// only opened for the read.
+func lastRun(statePath string, path string) (time.Time, error) {
+	if _, err := os.Stat(statePath); os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	state, err := store.OpenReadOnly(statePath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer state.Close()
+
+	return state.LastRun(path)
+}
+
+// setLastRun records the last run of a template in the state file, which is
+// only opened for the write.
+func setLastRun(statePath string, path string, t time.Time) error {
+	state, err := store.Open(statePath)
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+
+	return state.SetLastRun(path, t)
+}
+
+// newJob loads a template, applies the command line overrides and parses its schedule.
+func newJob(path string, options *types.Options) (*job, error) {
+	config, err := LoadTemplate(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	ApplyOptions(config, options)
+	if config.Schedule == "" {
+		return nil, fmt.Errorf("%s: no schedule provided", path)
+	}
+
+	sched, err := schedule.Parse(config.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	notifiers, err := notify.New(config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	j := &job{path: templateKey(path), config: config, schedule: sched, notifiers: notifiers}
+	if _, err := j.nextRun(time.Now()); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// nextRun returns the first activation after t, delayed by a random jitter.
+// A schedule without one, such as "0 0 30 2 *", is a configuration error.
+func (j *job) nextRun(t time.Time) (time.Time, error) {
+	next := j.schedule.Next(t)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%s: schedule %q never runs", j.path, j.config.Schedule)
+	}
+	if j.config.Jitter > 0 {
+		next = next.Add(rand.N(j.config.Jitter))
+	}
+	return next, nil
+}
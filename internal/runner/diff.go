@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/e1l1ya/findtarget/internal/diff"
+	"github.com/e1l1ya/findtarget/internal/output"
+)
+
+// Diff compares two runs saved with the json or jsonl format and writes the
+// differences as text or JSON to path, or to stdout when path is empty.
+func Diff(oldPath string, newPath string, format string, path string) error {
+	oldRecords, err := readRecords(oldPath)
+	if err != nil {
+		return err
+	}
+	newRecords, err := readRecords(newPath)
+	if err != nil {
+		return err
+	}
+
+	result := diff.Compare(oldRecords, newRecords)
+
+	var out io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch format {
+	case "", "text", "txt":
+		return diff.WriteText(out, result)
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	return fmt.Errorf("unknown diff format: %s", format)
+}
+
+// readRecords reads the records of a saved run.
+func readRecords(path string) ([]output.Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	records, err := output.ReadRecords(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+// History writes the assets recorded in the state file that match the query,
+// as text or JSON, to path or to stdout when path is empty.
+func History(options *types.Options, query store.Query, format string, path string) error {
+	statePath, err := statePath(options)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(statePath); err != nil {
+		return fmt.Errorf("state file not found: %s", statePath)
+	}
+
+	state, err := store.OpenReadOnly(statePath)
+	if err != nil {
+		return err
+	}
+	defer state.Close()
+
+	entries, err := state.History(query)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch format {
+	case "", "text", "txt":
+		for _, entry := range entries {
+			status := ""
+			if entry.Removed {
+				status = " (removed)"
+			} else if !entry.InScope {
+				status = " (out of scope)"
+			}
+			_, err := fmt.Fprintf(out, "%s  %s  %s/%s %s %s%s\n",
+				entry.FirstSeen.Format("2006-01-02"), entry.LastSeen.Format("2006-01-02"),
+				entry.Platform, entry.Program, entry.AssetType, entry.Identifier, status)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		if entries == nil {
+			entries = []store.HistoryEntry{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+	return fmt.Errorf("unknown history format: %s", format)
+}
+
+// ParseSince parses a --since value: a date (2006-01-02), an RFC 3339 time,
+// a number of days (30d) or a duration (12h), the last two counted back from now.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if since, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return since, nil
+	}
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since value: %s", value)
+}
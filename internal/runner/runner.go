@@ -0,0 +1,163 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/e1l1ya/findtarget/internal/notify"
+	"github.com/e1l1ya/findtarget/internal/output"
+	"github.com/e1l1ya/findtarget/internal/platform"
+	"github.com/e1l1ya/findtarget/internal/store"
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
+)
+
+// Run scans the platforms enabled in the template, writes the programs found
+// in the selected output format and records them in the state file. When ctx
+// is cancelled or the run times out, the programs found so far are still
+// written and recorded.
+func Run(ctx context.Context, config *types.Config, options *types.Options) error {
+	notifiers, err := notify.New(config)
+	if err != nil {
+		return err
+	}
+
+	writer, err := newWriter(config, options)
+	if err != nil {
+		return err
+	}
+	if options.OutputDir != "" {
+		dirWriter, err := output.NewDir(options.OutputDir)
+		if err != nil {
+			writer.Close()
+			return err
+		}
+		writer = output.Multi(writer, dirWriter)
+	}
+
+	ctx, cancel := runContext(ctx, options)
+	defer cancel()
+
+	var programs []types.Program
+	err = platform.Run(ctx, config, options.Concurrency, func(program types.Program) error {
+		programs = append(programs, program)
+		return writer.Write(program)
+	})
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+
+	if !options.NoState {
+		recordRun(options, notifiers, programs)
+	}
+	return partialError(err)
+}
+
+// runContext bounds a run by the --timeout flag.
+func runContext(ctx context.Context, options *types.Options) (context.Context, context.CancelFunc) {
+	if options.Timeout > 0 {
+		return context.WithTimeout(ctx, options.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// partialError explains a run stopped by its context. The programs found
+// before are complete, so they were written like those of a full run.
+func partialError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("interrupted, partial results written")
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("timed out, partial results written")
+	}
+	return err
+}
+
+// newWriter creates the output writer selected by the flags. An output
+// template is looked up by name in the template file before being parsed as
+// a Go text/template. The --output-dir layout is left to the caller.
+func newWriter(config *types.Config, options *types.Options) (output.Writer, error) {
+	if options.OutputTemplate != "" {
+		text, ok := config.OutputTemplates[options.OutputTemplate]
+		if !ok {
+			text = options.OutputTemplate
+		}
+		return output.NewTemplate(text, options.Output)
+	}
+	return output.New(options.Format, options.Output)
+}
+
+// recordRun adds the programs of a run to the state file and notifies the
+// sinks of the template about new assets. The state is only used for history
+// and notifications here, so failures are reported without failing the run.
+func recordRun(options *types.Options, notifiers []notify.Notifier, programs []types.Program) {
+	statePath, err := statePath(options)
+	if err != nil {
+		gologger.Warning().Msgf("Could not record run: %v", err)
+		return
+	}
+
+	if _, err := record(statePath, templateKey(options.Template), notifiers, programs); err != nil {
+		gologger.Warning().Msgf("Could not record run: %v", err)
+	}
+}
+
+// record opens the state file for the time it takes to record the programs of
+// a run, so watch mode, the daemon, history queries and other runs can share
+// it.
+func record(statePath string, template string, notifiers []notify.Notifier, programs []types.Program) (store.Changes, error) {
+	state, err := store.Open(statePath)
+	if err != nil {
+		return store.Changes{}, err
+	}
+	defer state.Close()
+
+	return updateState(state, template, notifiers, programs)
+}
+
+// updateState records the programs of a run of a template and notifies the
+// sinks about the changes. The first run of a template only seeds the state,
+// so it does not notify about every known asset.
+func updateState(state *store.Store, template string, notifiers []notify.Notifier, programs []types.Program) (store.Changes, error) {
+	empty, err := state.Empty(template)
+	if err != nil {
+		return store.Changes{}, err
+	}
+
+	now := time.Now()
+	changes, err := state.Update(template, programs, now)
+	if err != nil {
+		return store.Changes{}, err
+	}
+
+	if !empty {
+		notify.Send(notifiers, state, template, changes, now)
+	}
+	return changes, nil
+}
+
+// templateKey returns the absolute path identifying a template in the state
+// file, so a template keeps its state whatever directory it is run from.
+func templateKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// statePath returns the state file path, defaulting to the user config directory.
+func statePath(options *types.Options) (string, error) {
+	if options.State != "" {
+		return options.State, nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "findtarget", "state.db"), nil
+}
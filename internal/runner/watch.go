@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/e1l1ya/findtarget/internal/notify"
+	"github.com/e1l1ya/findtarget/internal/output"
+	"github.com/e1l1ya/findtarget/internal/platform"
+	"github.com/e1l1ya/findtarget/pkg/types"
+	"github.com/projectdiscovery/gologger"
+)
+
+// Watch runs the platforms every interval and writes only the programs and
+// assets that were not seen by a previous cycle, using the state file to
+// remember them across restarts. The state file is only opened while a cycle
+// records its programs. It returns once ctx is cancelled, after writing what
+// the current cycle found.
+func Watch(ctx context.Context, config *types.Config, options *types.Options) error {
+	if err := checkStreamable(options); err != nil {
+		return err
+	}
+
+	notifiers, err := notify.New(config)
+	if err != nil {
+		return err
+	}
+
+	statePath, err := statePath(options)
+	if err != nil {
+		return err
+	}
+
+	writer, err := newWriter(config, options)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+
+	dirWriter, err := newDirWriter(options)
+	if err != nil {
+		return err
+	}
+
+	for {
+		err := watchCycle(ctx, templateKey(options.Template), config, options, statePath, notifiers, writer, dirWriter)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			gologger.Error().Msgf("Watch cycle failed: %v", err)
+		}
+
+		gologger.Info().Msgf("Next run in %s", options.Watch)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(options.Watch):
+		}
+	}
+}
+
+// watchCycle runs the platforms of a template once and writes the new
+// programs and assets, and with --removed the assets that left the scope. The
+// --output-dir layout of every program found is rewritten with its full
+// scope. The programs found before a failure or an interruption are still
+// recorded and written before the error is returned.
+func watchCycle(ctx context.Context, template string, config *types.Config, options *types.Options, statePath string, notifiers []notify.Notifier, writer output.Writer, dirWriter output.Writer) error {
+	ctx, cancel := runContext(ctx, options)
+	defer cancel()
+
+	programs, runErr := collect(ctx, config, options)
+
+	changes, err := record(statePath, template, notifiers, programs)
+	if err != nil {
+		return err
+	}
+
+	for _, program := range changes.Added {
+		if err := writer.Write(program); err != nil {
+			return err
+		}
+	}
+
+	if options.Removed {
+		for _, program := range changes.Removed {
+			for i := range program.Assets {
+				program.Assets[i].Removed = true
+			}
+			if err := writer.Write(program); err != nil {
+				return err
+			}
+		}
+	}
+
+	if dirWriter != nil {
+		for _, program := range programs {
+			if err := dirWriter.Write(program); err != nil {
+				return err
+			}
+		}
+	}
+	return partialError(runErr)
+}
+
+// checkStreamable rejects the formats that are only valid once the output is
+// closed, since watch mode and the daemon keep it open until they stop.
+func checkStreamable(options *types.Options) error {
+	if options.OutputTemplate != "" {
+		return nil
+	}
+	switch options.Format {
+	case "json", "burp", "zap":
+		return fmt.Errorf("format %s is only complete once the output is closed, use jsonl or txt with --watch and the daemon", options.Format)
+	}
+	return nil
+}
+
+// newDirWriter creates the --output-dir writer, or returns nil without the flag.
+func newDirWriter(options *types.Options) (output.Writer, error) {
+	if options.OutputDir == "" {
+		return nil, nil
+	}
+	return output.NewDir(options.OutputDir)
+}
+
+// collect runs the platforms and returns every program found. Programs found
+// before a platform fails are still returned along with the error.
+func collect(ctx context.Context, config *types.Config, options *types.Options) ([]types.Program, error) {
+	var programs []types.Program
+	err := platform.Run(ctx, config, options.Concurrency, func(program types.Program) error {
+		programs = append(programs, program)
+		return nil
+	})
+	return programs, err
+}
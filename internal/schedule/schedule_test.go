@@ -0,0 +1,163 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+// activations returns the first n activations of expr after start.
+func activations(t *testing.T, expr string, start time.Time, n int) []time.Time {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	var got []time.Time
+	for next := start; len(got) < n; {
+		next = s.Next(next)
+		if next.IsZero() {
+			break
+		}
+		got = append(got, next)
+	}
+	return got
+}
+
+// utc parses a "2006-01-02 15:04" time in UTC.
+func utc(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		start string
+		want  []string
+	}{
+		{"every quarter", "*/15 * * * *", "2026-01-01 10:07", []string{"2026-01-01 10:15", "2026-01-01 10:30", "2026-01-01 10:45", "2026-01-01 11:00"}},
+		{"ranged step", "1-10/3 0 * * *", "2026-01-01 00:00", []string{"2026-01-01 00:01", "2026-01-01 00:04", "2026-01-01 00:07", "2026-01-01 00:10", "2026-01-02 00:01"}},
+		{"step from value", "5/10 0 * * *", "2026-01-01 00:30", []string{"2026-01-01 00:35", "2026-01-01 00:45", "2026-01-01 00:55", "2026-01-02 00:05"}},
+		{"list", "0 8,20 * * *", "2026-01-01 09:00", []string{"2026-01-01 20:00", "2026-01-02 08:00"}},
+		{"strictly after", "30 9 * * *", "2026-01-01 09:30", []string{"2026-01-02 09:30"}},
+		{"names", "0 9 * jan,jul MON-wed", "2026-01-29 12:00", []string{"2026-07-01 09:00", "2026-07-06 09:00", "2026-07-07 09:00"}},
+		{"sunday as 0", "0 0 * * 0", "2026-01-01 00:00", []string{"2026-01-04 00:00", "2026-01-11 00:00"}},
+		{"sunday as 7", "0 0 * * 7", "2026-01-01 00:00", []string{"2026-01-04 00:00", "2026-01-11 00:00"}},
+		{"sunday range", "0 0 * * 5-7", "2026-01-01 00:00", []string{"2026-01-02 00:00", "2026-01-03 00:00", "2026-01-04 00:00", "2026-01-09 00:00"}},
+		// The 13th or a Friday when both day fields are restricted
+		{"day or weekday", "0 0 13 * fri", "2026-01-01 00:00", []string{"2026-01-02 00:00", "2026-01-09 00:00", "2026-01-13 00:00", "2026-01-16 00:00"}},
+		// Only the restricted field counts when the other one is "*"
+		{"day only", "0 0 13 * *", "2026-01-01 00:00", []string{"2026-01-13 00:00", "2026-02-13 00:00"}},
+		{"weekday only", "0 0 * * fri", "2026-01-01 00:00", []string{"2026-01-02 00:00", "2026-01-09 00:00"}},
+		{"month rollover", "0 0 31 * *", "2026-01-31 12:00", []string{"2026-03-31 00:00", "2026-05-31 00:00"}},
+		{"year rollover", "59 23 31 12 *", "2026-06-01 00:00", []string{"2026-12-31 23:59", "2027-12-31 23:59"}},
+		{"leap day", "0 0 29 2 *", "2026-01-01 00:00", []string{"2028-02-29 00:00"}},
+		{"monthly", "@monthly", "2026-01-15 00:00", []string{"2026-02-01 00:00", "2026-03-01 00:00"}},
+		{"weekly", "@weekly", "2026-01-01 00:00", []string{"2026-01-04 00:00"}},
+		// Nothing within the 5 year bound returns the zero time
+		{"never", "0 0 30 2 *", "2026-01-01 00:00", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := activations(t, test.expr, utc(t, test.start), len(test.want))
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d activations %v, want %v", len(got), got, test.want)
+			}
+			for i, want := range test.want {
+				if !got[i].Equal(utc(t, want)) {
+					t.Errorf("activation %d = %s, want %s", i, got[i].Format("2006-01-02 15:04"), want)
+				}
+			}
+		})
+	}
+}
+
+func TestEvery(t *testing.T) {
+	s, err := Parse("@every 90m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := utc(t, "2026-01-01 10:07")
+	if next := s.Next(start); !next.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("Next = %s", next)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"10-5 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"* * * foo *",
+		"@every 500ms",
+		"@every 0s",
+		"@every -1m",
+		"@every soon",
+		"@fortnightly",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded", expr)
+		}
+	}
+}
+
+func TestNextDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	local := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04 MST", value, berlin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name  string
+		expr  string
+		start string
+		want  []string
+	}{
+		// Clocks go from 02:00 CET to 03:00 CEST on 2026-03-29
+		{"same local time", "0 9 * * *", "2026-03-28 12:00 CET", []string{"2026-03-29 09:00 CEST", "2026-03-30 09:00 CEST"}},
+		{"skipped hour", "30 2 * * *", "2026-03-28 12:00 CET", []string{"2026-03-30 02:30 CEST"}},
+		{"hourly over the gap", "0 * * * *", "2026-03-29 00:30 CET", []string{"2026-03-29 01:00 CET", "2026-03-29 03:00 CEST", "2026-03-29 04:00 CEST"}},
+		// Clocks go from 03:00 CEST back to 02:00 CET on 2026-10-25
+		{"repeated hour", "30 2 * * *", "2026-10-24 12:00 CEST", []string{"2026-10-25 02:30 CET", "2026-10-26 02:30 CET"}},
+		{"within the repeated hour", "45 2 * * *", "2026-10-25 02:10 CEST", []string{"2026-10-25 02:45 CET", "2026-10-26 02:45 CET"}},
+		{"hourly over the overlap", "0 * * * *", "2026-10-25 01:30 CEST", []string{"2026-10-25 02:00 CET", "2026-10-25 03:00 CET"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := activations(t, test.expr, local(test.start), len(test.want))
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d activations %v, want %v", len(got), got, test.want)
+			}
+			for i, want := range test.want {
+				if !got[i].Equal(local(want)) {
+					t.Errorf("activation %d = %s, want %s", i, got[i].Format("2006-01-02 15:04 MST"), want)
+				}
+				if got[i].Location() != berlin {
+					t.Errorf("activation %d is in %s", i, got[i].Location())
+				}
+			}
+		})
+	}
+}
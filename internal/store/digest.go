@@ -0,0 +1,105 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// digestsBucket holds one nested bucket per template with the pending digest
+// of every batching sink, keyed by the sink name.
+var digestsBucket = []byte("digests")
+
+// Digest is the batch of changes waiting to be sent by a sink.
+type Digest struct {
+	LastSent time.Time `json:"last_sent"`
+	Changes
+}
+
+// QueueDigest adds the changes to the pending digest of a sink of a template
+// and returns the digest. The assets of a program queued several times are
+// merged, the latest values of an asset replacing the queued ones.
+func (s *Store) QueueDigest(template string, name string, changes Changes, now time.Time) (Digest, error) {
+	digest := Digest{LastSent: now}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := digestBucket(tx, template)
+		if err != nil {
+			return err
+		}
+		if data := bucket.Get([]byte(name)); data != nil {
+			if err := json.Unmarshal(data, &digest); err != nil {
+				return err
+			}
+		}
+
+		for _, program := range changes.Added {
+			digest.Added = mergeProgram(digest.Added, program)
+		}
+		for _, program := range changes.Changed {
+			digest.Changed = mergeProgram(digest.Changed, program)
+		}
+		for _, program := range changes.Removed {
+			digest.Removed = mergeProgram(digest.Removed, program)
+		}
+		return putJSON(bucket, []byte(name), digest)
+	})
+	if err != nil {
+		return Digest{}, fmt.Errorf("failed to queue digest: %v", err)
+	}
+
+	return digest, nil
+}
+
+// ClearDigest empties the pending digest of a sink of a template once it was
+// sent.
+func (s *Store) ClearDigest(template string, name string, now time.Time) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := digestBucket(tx, template)
+		if err != nil {
+			return err
+		}
+		return putJSON(bucket, []byte(name), Digest{LastSent: now})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear digest: %v", err)
+	}
+	return nil
+}
+
+// digestBucket returns the bucket holding the digests of a template.
+func digestBucket(tx *bolt.Tx, template string) (*bolt.Bucket, error) {
+	root, err := tx.CreateBucketIfNotExists(digestsBucket)
+	if err != nil {
+		return nil, err
+	}
+	return root.CreateBucketIfNotExists([]byte(template))
+}
+
+// mergeProgram adds a program to the list, merging its assets into an
+// existing entry of the same program without duplicating them.
+func mergeProgram(programs []types.Program, program types.Program) []types.Program {
+	key := string(programKey(program))
+	for i := range programs {
+		if string(programKey(programs[i])) != key {
+			continue
+		}
+
+		seen := make(map[string]int)
+		for j, asset := range programs[i].Assets {
+			seen[assetKey(asset)] = j
+		}
+		for _, asset := range program.Assets {
+			if j, ok := seen[assetKey(asset)]; ok {
+				programs[i].Assets[j] = asset
+				continue
+			}
+			programs[i].Assets = append(programs[i].Assets, asset)
+		}
+		return programs
+	}
+	return append(programs, program)
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Query selects the assets returned by History. Empty fields match everything.
+type Query struct {
+	Platform string
+	Program  string
+	Since    time.Time // Only assets first seen at or after Since
+}
+
+// HistoryEntry is an asset with the times it was first and last seen.
+type HistoryEntry struct {
+	Platform   string    `json:"platform"`
+	Program    string    `json:"program"`
+	ProgramURL string    `json:"program_url"`
+	AssetType  string    `json:"asset_type"`
+	Identifier string    `json:"identifier"`
+	InScope    bool      `json:"in_scope"`
+	Bounty     bool      `json:"bounty"`
+	FirstSeen  time.Time `json:"first_seen"`
+	LastSeen   time.Time `json:"last_seen"`
+	Removed    bool      `json:"removed"`
+}
+
+// History returns the assets matching the query, ordered by program and then
+// by first-seen time. An asset recorded by several templates is reported once,
+// first seen when any template first saw it and with the state of the
+// template that saw it last.
+func (s *Store) History(query Query) ([]HistoryEntry, error) {
+	type program struct {
+		url    string
+		assets map[string]*HistoryEntry
+	}
+	programs := make(map[string]*program)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		templates := tx.Bucket(templatesBucket)
+		if templates == nil {
+			// A read-only store may predate the first recorded run
+			return nil
+		}
+		return templates.ForEachBucket(func(template []byte) error {
+			root := templates.Bucket(template)
+			return root.ForEachBucket(func(key []byte) error {
+				platform, handle, _ := strings.Cut(string(key), "/")
+				if query.Platform != "" && !strings.EqualFold(query.Platform, platform) {
+					return nil
+				}
+				if query.Program != "" && !strings.EqualFold(query.Program, handle) {
+					return nil
+				}
+
+				bucket := root.Bucket(key)
+				var meta storedProgram
+				if data := bucket.Get(metaKey); data != nil {
+					if err := json.Unmarshal(data, &meta); err != nil {
+						return err
+					}
+				}
+				p, ok := programs[string(key)]
+				if !ok {
+					p = &program{assets: make(map[string]*HistoryEntry)}
+					programs[string(key)] = p
+				}
+				if p.url == "" {
+					p.url = meta.URL
+				}
+
+				assets := bucket.Bucket(assetsBucket)
+				if assets == nil {
+					return nil
+				}
+				return assets.ForEach(func(k, v []byte) error {
+					var stored storedAsset
+					if err := json.Unmarshal(v, &stored); err != nil {
+						return err
+					}
+					entry := &HistoryEntry{
+						Platform:   platform,
+						Program:    handle,
+						AssetType:  string(stored.Kind),
+						Identifier: stored.Identifier,
+						InScope:    stored.InScope,
+						Bounty:     stored.BountyEligible,
+						FirstSeen:  stored.FirstSeen,
+						LastSeen:   stored.LastSeen,
+						Removed:    stored.Removed,
+					}
+
+					seen, ok := p.assets[string(k)]
+					if !ok {
+						p.assets[string(k)] = entry
+						return nil
+					}
+					firstSeen := seen.FirstSeen
+					if entry.LastSeen.After(seen.LastSeen) {
+						*seen = *entry
+					}
+					if firstSeen.Before(seen.FirstSeen) {
+						seen.FirstSeen = firstSeen
+					}
+					return nil
+				})
+			})
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %v", err)
+	}
+
+	keys := make([]string, 0, len(programs))
+	for key := range programs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var entries []HistoryEntry
+	for _, key := range keys {
+		p := programs[key]
+		assetKeys := make([]string, 0, len(p.assets))
+		for assetKey := range p.assets {
+			assetKeys = append(assetKeys, assetKey)
+		}
+		sort.Strings(assetKeys)
+
+		var programEntries []HistoryEntry
+		for _, assetKey := range assetKeys {
+			entry := p.assets[assetKey]
+			if entry.FirstSeen.Before(query.Since) {
+				continue
+			}
+			entry.ProgramURL = p.url
+			programEntries = append(programEntries, *entry)
+		}
+
+		// Assets seen in the same run keep their key order
+		sort.SliceStable(programEntries, func(i, j int) bool {
+			return programEntries[i].FirstSeen.Before(programEntries[j].FirstSeen)
+		})
+		entries = append(entries, programEntries...)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// runsBucket holds the last run time of every daemon job, keyed by the
+// template path.
+var runsBucket = []byte("runs")
+
+// LastRun returns the last time a job ran, or the zero time if it never did.
+func (s *Store) LastRun(name string) (time.Time, error) {
+	var last time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(runsBucket)
+		if bucket == nil {
+			return nil
+		}
+		if data := bucket.Get([]byte(name)); data != nil {
+			return last.UnmarshalText(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read last run: %v", err)
+	}
+	return last, nil
+}
+
+// SetLastRun records the time a job ran.
+func (s *Store) SetLastRun(name string, t time.Time) error {
+	data, err := t.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(runsBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(name), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record last run: %v", err)
+	}
+	return nil
+}
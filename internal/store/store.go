@@ -0,0 +1,288 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// templatesBucket holds one nested bucket per template, keyed by its path.
+// Every template bucket holds one bucket per program, keyed by
+// "<platform>/<handle>", with the program metadata and its assets. Templates
+// are kept apart since their filters select different assets of the same
+// programs.
+var templatesBucket = []byte("templates")
+
+var (
+	metaKey      = []byte("meta")
+	assetsBucket = []byte("assets")
+)
+
+// Store persists the programs and assets seen across runs in a bbolt file.
+type Store struct {
+	db *bolt.DB
+}
+
+// storedProgram is the metadata of a program as persisted.
+type storedProgram struct {
+	types.Program
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// storedAsset is an asset as persisted. Assets that left the scope are kept
+// with Removed set so their history stays available.
+type storedAsset struct {
+	types.Asset
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Removed   bool      `json:"removed,omitempty"`
+}
+
+// Changes lists what a run added to, changed in or removed from the store.
+// Every program only holds its added, changed or removed assets. Changed
+// assets were already in scope and changed their scope, bounty eligibility or
+// max severity; they hold their new values.
+type Changes struct {
+	Added   []types.Program `json:"added,omitempty"`
+	Changed []types.Program `json:"changed,omitempty"`
+	Removed []types.Program `json:"removed,omitempty"`
+}
+
+// Empty reports whether nothing changed.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Changed) == 0 && len(c.Removed) == 0
+}
+
+// writeTimeout bounds the wait for the state file lock held by another
+// process recording a run, which includes sending its notifications.
+const writeTimeout = time.Minute
+
+// readTimeout bounds the wait for a read-only open of the state file.
+const readTimeout = 5 * time.Second
+
+// Open opens the store at path for writing, creating it when needed. The file
+// is locked until the store is closed, so callers keep it open only for the
+// time it takes to record a run.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: writeTimeout})
+	if err != nil {
+		return nil, openError(err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(templatesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise state: %v", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// OpenReadOnly opens the store at path for reading. Readers share the file
+// but wait for a writer to close it.
+func OpenReadOnly(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{ReadOnly: true, Timeout: readTimeout})
+	if err != nil {
+		return nil, openError(err)
+	}
+	return &Store{db: db}, nil
+}
+
+// openError explains why the state file could not be opened.
+func openError(err error) error {
+	if errors.Is(err, bolt.ErrTimeout) {
+		return fmt.Errorf("failed to open state: in use by another findtarget process")
+	}
+	return fmt.Errorf("failed to open state: %v", err)
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Empty reports whether no program was recorded yet for a template.
+func (s *Store) Empty(template string) (bool, error) {
+	empty := true
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var root *bolt.Bucket
+		if templates := tx.Bucket(templatesBucket); templates != nil {
+			root = templates.Bucket([]byte(template))
+		}
+		if root != nil {
+			key, _ := root.Cursor().First()
+			empty = key == nil
+		}
+		return nil
+	})
+	return empty, err
+}
+
+// Update records the programs of a run of a template and returns the in-scope
+// assets that were not seen before or changed since the previous run. Assets
+// that left the scope of a program of this run are reported as removed;
+// programs missing from the run are left untouched since a run may be limited
+// by maxPrograms.
+func (s *Store) Update(template string, programs []types.Program, now time.Time) (Changes, error) {
+	var changes Changes
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		root, err := tx.Bucket(templatesBucket).CreateBucketIfNotExists([]byte(template))
+		if err != nil {
+			return err
+		}
+
+		for _, program := range programs {
+			bucket, err := root.CreateBucketIfNotExists(programKey(program))
+			if err != nil {
+				return err
+			}
+
+			meta := storedProgram{FirstSeen: now}
+			if data := bucket.Get(metaKey); data != nil {
+				if err := json.Unmarshal(data, &meta); err != nil {
+					return err
+				}
+			}
+			meta.Program = program
+			meta.Program.Assets = nil
+			meta.LastSeen = now
+			if err := putJSON(bucket, metaKey, meta); err != nil {
+				return err
+			}
+
+			added, changed, removed, err := updateAssets(bucket, program, now)
+			if err != nil {
+				return err
+			}
+			if len(added.Assets) > 0 {
+				changes.Added = append(changes.Added, added)
+			}
+			if len(changed.Assets) > 0 {
+				changes.Changed = append(changes.Changed, changed)
+			}
+			if len(removed.Assets) > 0 {
+				changes.Removed = append(changes.Removed, removed)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Changes{}, fmt.Errorf("failed to update state: %v", err)
+	}
+
+	return changes, nil
+}
+
+// updateAssets records the assets of a program bucket and returns copies of
+// the program holding the added, changed and removed in-scope assets.
+func updateAssets(bucket *bolt.Bucket, program types.Program, now time.Time) (added, changed, removed types.Program, err error) {
+	added, changed, removed = program, program, program
+	added.Assets, changed.Assets, removed.Assets = nil, nil, nil
+
+	assets, err := bucket.CreateBucketIfNotExists(assetsBucket)
+	if err != nil {
+		return added, changed, removed, err
+	}
+
+	current := make(map[string]bool)
+	for _, asset := range program.Assets {
+		key := assetKey(asset)
+		current[key] = true
+
+		stored := storedAsset{FirstSeen: now}
+		seen := false
+		if data := assets.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, &stored); err != nil {
+				return added, changed, removed, err
+			}
+			seen = !stored.Removed
+		}
+		switch {
+		case asset.InScope && (!seen || !stored.InScope):
+			added.Assets = append(added.Assets, asset)
+		case seen && stored.InScope && (!asset.InScope ||
+			asset.BountyEligible != stored.BountyEligible ||
+			asset.MaxSeverity != stored.MaxSeverity):
+			changed.Assets = append(changed.Assets, asset)
+		}
+		stored.Asset = asset
+		stored.LastSeen = now
+		stored.Removed = false
+
+		if err := putJSON(assets, []byte(key), stored); err != nil {
+			return added, changed, removed, err
+		}
+	}
+
+	// Keys are visited in byte order, which keeps the removed assets sorted.
+	// The bucket cannot be modified while it is iterated, so the removed
+	// assets are marked afterwards.
+	var gone []string
+	err = assets.ForEach(func(k, v []byte) error {
+		if current[string(k)] {
+			return nil
+		}
+
+		var stored storedAsset
+		if err := json.Unmarshal(v, &stored); err != nil {
+			return err
+		}
+		if !stored.Removed {
+			gone = append(gone, string(k))
+			if stored.InScope {
+				removed.Assets = append(removed.Assets, stored.Asset)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return added, changed, removed, err
+	}
+
+	for _, key := range gone {
+		var stored storedAsset
+		if err := json.Unmarshal(assets.Get([]byte(key)), &stored); err != nil {
+			return added, changed, removed, err
+		}
+		stored.Removed = true
+		if err := putJSON(assets, []byte(key), stored); err != nil {
+			return added, changed, removed, err
+		}
+	}
+
+	return added, changed, removed, nil
+}
+
+// programKey returns the bucket key of a program.
+func programKey(program types.Program) []byte {
+	return []byte(program.Platform + "/" + program.Handle)
+}
+
+// assetKey returns the key of an asset within its program.
+func assetKey(asset types.Asset) string {
+	return string(asset.Kind) + " " + asset.Identifier
+}
+
+// putJSON stores v as JSON under key.
+func putJSON(bucket *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, data)
+}
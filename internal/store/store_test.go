@@ -0,0 +1,137 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/e1l1ya/findtarget/pkg/types"
+)
+
+func TestUpdateKeepsTemplatesApart(t *testing.T) {
+	state, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer state.Close()
+
+	program := func(assets ...types.Asset) []types.Program {
+		return []types.Program{{Platform: "hackerone", Handle: "acme", Assets: assets}}
+	}
+	url := types.Asset{Kind: types.AssetURL, Identifier: "www.acme.com", InScope: true}
+	wildcard := types.Asset{Kind: types.AssetWildcard, Identifier: "*.acme.com", InScope: true}
+	narrow, wide := "/templates/narrow.yaml", "/templates/wide.yaml"
+
+	now := time.Now()
+	for cycle := 0; cycle < 2; cycle++ {
+		for _, run := range []struct {
+			template string
+			assets   []types.Asset
+		}{
+			{narrow, []types.Asset{url}},
+			{wide, []types.Asset{wildcard}},
+		} {
+			empty, err := state.Empty(run.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if empty != (cycle == 0) {
+				t.Errorf("cycle %d: Empty(%s) = %t", cycle, run.template, empty)
+			}
+
+			now = now.Add(time.Hour)
+			changes, err := state.Update(run.template, program(run.assets...), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cycle > 0 && !changes.Empty() {
+				t.Errorf("cycle %d: %s reported changes %+v", cycle, run.template, changes)
+			}
+		}
+	}
+
+	entries, err := state.History(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("history has %d entries, want 2", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.Removed {
+			t.Errorf("%s reported as removed", entry.Identifier)
+		}
+	}
+}
+
+func TestUpdateChanges(t *testing.T) {
+	state, err := Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer state.Close()
+
+	const template = "/templates/all.yaml"
+	kept := types.Asset{Kind: types.AssetURL, Identifier: "www.acme.com", InScope: true}
+	dropped := types.Asset{Kind: types.AssetURL, Identifier: "old.acme.com", InScope: true}
+	now := time.Now()
+	if _, err := state.Update(template, []types.Program{{Platform: "hackerone", Handle: "acme", Assets: []types.Asset{kept, dropped}}}, now); err != nil {
+		t.Fatal(err)
+	}
+
+	kept.BountyEligible = true
+	added := types.Asset{Kind: types.AssetWildcard, Identifier: "*.acme.com", InScope: true}
+	changes, err := state.Update(template, []types.Program{{Platform: "hackerone", Handle: "acme", Assets: []types.Asset{kept, added}}}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, got := range map[string][]types.Program{"added": changes.Added, "changed": changes.Changed, "removed": changes.Removed} {
+		if len(got) != 1 || len(got[0].Assets) != 1 {
+			t.Errorf("%s = %+v, want one asset", name, got)
+		}
+	}
+	if t.Failed() {
+		return
+	}
+	if changes.Added[0].Assets[0].Identifier != added.Identifier ||
+		changes.Changed[0].Assets[0].Identifier != kept.Identifier || !changes.Changed[0].Assets[0].BountyEligible ||
+		changes.Removed[0].Assets[0].Identifier != dropped.Identifier {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+}
+
+func TestOpenReadOnlyShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	state, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asset := types.Asset{Kind: types.AssetURL, Identifier: "www.acme.com", InScope: true}
+	if _, err := state.Update("/templates/all.yaml", []types.Program{{Platform: "hackerone", Handle: "acme", Assets: []types.Asset{asset}}}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	// The writer releases the file once closed
+	if err := state.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+	second, err := OpenReadOnly(path)
+	if err != nil {
+		t.Fatalf("second reader: %v", err)
+	}
+	defer second.Close()
+
+	entries, err := second.History(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Identifier != asset.Identifier {
+		t.Errorf("history = %+v", entries)
+	}
+}
@@ -1,18 +1,13 @@
 package types
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
 // BugCrowdConfig struct
 type BugCrowdConfig struct {
-	Reward      string `yaml:"reward"`
-	Category    string `yaml:"category"`
-	Scope       string `yaml:"scope"`
-	MaxPrograms int16  `yaml:"maxPrograms"`
-	Include     []string `yaml:"include"`
-}
-
-func (b *BugCrowdConfig) SetDefaults() {
-	if b.Scope == "" { // Ensuring WideScope is explicitly set to false
-		b.Scope = "all"
-	}
+	PlatformConfig `yaml:",inline"`
 }
 
 // Define the structure of the response JSON
@@ -36,12 +31,31 @@ type Engagement struct {
 }
 
 type ScopeItem struct {
-	Name    string   `json:"name"`
-	Targets []Target `json:"targets"`
+	Name            string          `json:"name"`
+	InScope         *bool           `json:"inScope"`
+	RewardRangeData json.RawMessage `json:"rewardRangeData"`
+	Targets         []Target        `json:"targets"`
+}
+
+// IsInScope reports whether the scope group lists in-scope targets. Groups
+// without the flag are treated as in scope.
+func (s ScopeItem) IsInScope() bool {
+	return s.InScope == nil || *s.InScope
+}
+
+// HasRewards reports whether the scope group carries a reward range.
+func (s ScopeItem) HasRewards() bool {
+	data := bytes.TrimSpace(s.RewardRangeData)
+	switch string(data) {
+	case "", "null", "{}", "[]":
+		return false
+	}
+	return true
 }
 
 type Target struct {
-	Name     string `json:"name"`
-	URI      string `json:"uri"`
-	Category string `json:"category"`
+	Name        string `json:"name"`
+	URI         string `json:"uri"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
 }
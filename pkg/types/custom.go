@@ -0,0 +1,16 @@
+package types
+
+// CustomConfig struct
+type CustomConfig struct {
+	PlatformConfig `yaml:",inline"`
+	Programs       []CustomProgram `yaml:"programs"`
+}
+
+// CustomProgram is a private program or engagement declared in the template.
+type CustomProgram struct {
+	Name       string   `yaml:"name"`
+	URL        string   `yaml:"url"`
+	Bounty     bool     `yaml:"bounty"`
+	InScope    []string `yaml:"inScope"`
+	OutOfScope []string `yaml:"outOfScope"`
+}
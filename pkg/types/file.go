@@ -0,0 +1,73 @@
+package types
+
+// FileConfig struct
+type FileConfig struct {
+	PlatformConfig `yaml:",inline"`
+	Path           string `yaml:"path"` // Dataset file or directory holding the bounty-targets dumps
+}
+
+// HackerOneDataset is a program of hackerone_data.json.
+type HackerOneDataset struct {
+	Handle         string `json:"handle"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	OffersBounties bool   `json:"offers_bounties"`
+	Targets        struct {
+		InScope    []H1Attributes `json:"in_scope"`
+		OutOfScope []H1Attributes `json:"out_of_scope"`
+	} `json:"targets"`
+}
+
+// BugcrowdDataset is a program of bugcrowd_data.json.
+type BugcrowdDataset struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	MaxPayout int    `json:"max_payout"`
+	Targets   struct {
+		InScope    []BugcrowdDatasetTarget `json:"in_scope"`
+		OutOfScope []BugcrowdDatasetTarget `json:"out_of_scope"`
+	} `json:"targets"`
+}
+
+type BugcrowdDatasetTarget struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	URI    string `json:"uri"`
+}
+
+// IntigritiDataset is a program of intigriti_data.json.
+type IntigritiDataset struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	CompanyHandle string          `json:"company_handle"`
+	Handle        string          `json:"handle"`
+	URL           string          `json:"url"`
+	MaxBounty     IntigritiAmount `json:"max_bounty"`
+	Targets       struct {
+		InScope    []IntigritiDatasetTarget `json:"in_scope"`
+		OutOfScope []IntigritiDatasetTarget `json:"out_of_scope"`
+	} `json:"targets"`
+}
+
+type IntigritiDatasetTarget struct {
+	Type        string `json:"type"`
+	Endpoint    string `json:"endpoint"`
+	Description string `json:"description"`
+}
+
+// YesWeHackDataset is a program of yeswehack_data.json.
+type YesWeHackDataset struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Disabled  bool    `json:"disabled"`
+	MaxBounty float64 `json:"max_bounty"`
+	Targets   struct {
+		InScope    []YesWeHackDatasetTarget `json:"in_scope"`
+		OutOfScope []YesWeHackDatasetTarget `json:"out_of_scope"`
+	} `json:"targets"`
+}
+
+type YesWeHackDatasetTarget struct {
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
@@ -20,13 +20,9 @@ type HackerOneLink struct {
 }
 
 type HackerOneConfig struct {
-	Reward      string `yaml:"reward"`
-	Category    string `yaml:"category"`
-	Scope       string `yaml:"scope"`
-	MaxPrograms int8   `yaml:"maxPrograms"`
-	Include     []string `yaml:"include"`
-	H1Username  string   `yaml:"h1Username"`
-	H1Token  string   `yaml:"h1Token"`
+	PlatformConfig `yaml:",inline"`
+	H1Username     string `yaml:"h1Username"`
+	H1Token        string `yaml:"h1Token"`
 }
 
 type H1ProgramStruct struct {
@@ -59,9 +55,3 @@ type H1Links struct {
 	Next string `json:"next"`
 	Last string `json:"last"`
 }
-
-func (b *HackerOneConfig) SetDefaults() {
-	if b.Scope == "" { // Ensuring WideScope is explicitly set to false
-		b.Scope = "all"
-	}
-}
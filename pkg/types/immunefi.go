@@ -0,0 +1,21 @@
+package types
+
+// ImmunefiConfig struct
+type ImmunefiConfig struct {
+	PlatformConfig `yaml:",inline"`
+}
+
+// ImmunefiBounty is a bounty from the public Immunefi listing.
+type ImmunefiBounty struct {
+	ID        string          `json:"id"`
+	Project   string          `json:"project"`
+	Slug      string          `json:"slug"`
+	MaxBounty float64         `json:"maxBounty"`
+	Assets    []ImmunefiAsset `json:"assets"`
+}
+
+type ImmunefiAsset struct {
+	Type        string `json:"type"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,56 @@
+package types
+
+// IntigritiConfig struct
+type IntigritiConfig struct {
+	PlatformConfig `yaml:",inline"`
+	Token          string `yaml:"token"`
+	APIURL         string `yaml:"apiURL"`
+}
+
+// IntigritiResponse is a page of the researcher API program listing.
+type IntigritiResponse struct {
+	MaxCount int                `json:"maxCount"`
+	Records  []IntigritiProgram `json:"records"`
+}
+
+// IntigritiProgram describes a program in the listing and in the program details.
+type IntigritiProgram struct {
+	ID        string            `json:"id"`
+	Handle    string            `json:"handle"`
+	Name      string            `json:"name"`
+	MinBounty IntigritiAmount   `json:"minBounty"`
+	MaxBounty IntigritiAmount   `json:"maxBounty"`
+	Status    IntigritiValue    `json:"status"`
+	Domains   *IntigritiDomains `json:"domains"`
+	WebLinks  struct {
+		Detail string `json:"detail"`
+	} `json:"webLinks"`
+}
+
+type IntigritiAmount struct {
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+}
+
+type IntigritiValue struct {
+	ID    int    `json:"id"`
+	Value string `json:"value"`
+}
+
+type IntigritiDomains struct {
+	Content []IntigritiDomain `json:"content"`
+}
+
+type IntigritiDomain struct {
+	Type        IntigritiValue `json:"type"`
+	Endpoint    string         `json:"endpoint"`
+	Tier        IntigritiValue `json:"tier"`
+	Description string         `json:"description"`
+}
+
+func (i *IntigritiConfig) SetDefaults() {
+	i.PlatformConfig.SetDefaults()
+	if i.APIURL == "" {
+		i.APIURL = "https://api.intigriti.com/external/researcher/v1"
+	}
+}
@@ -0,0 +1,54 @@
+package types
+
+// NotifyConfig declares the sinks notified about new programs and assets.
+type NotifyConfig struct {
+	Slack    *WebhookSinkConfig  `yaml:"slack"`
+	Discord  *WebhookSinkConfig  `yaml:"discord"`
+	Telegram *TelegramSinkConfig `yaml:"telegram"`
+	Webhook  *WebhookSinkConfig  `yaml:"webhook"`
+	Email    *EmailSinkConfig    `yaml:"email"`
+}
+
+// WebhookSinkConfig is an incoming webhook URL with optional extra headers.
+type WebhookSinkConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// TelegramSinkConfig holds the Telegram bot credentials.
+type TelegramSinkConfig struct {
+	Token  string `yaml:"token"`
+	ChatID string `yaml:"chatId"`
+	APIURL string `yaml:"apiURL"`
+}
+
+func (t *TelegramSinkConfig) SetDefaults() {
+	if t.APIURL == "" {
+		t.APIURL = "https://api.telegram.org"
+	}
+}
+
+// EmailSinkConfig holds the SMTP server and recipients of the email digest.
+type EmailSinkConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	TLS      bool     `yaml:"tls"` // Implicit TLS, as on port 465
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Subject  string   `yaml:"subject"`
+	Batch    string   `yaml:"batch"` // "run", "daily" or a duration such as "12h"
+}
+
+func (e *EmailSinkConfig) SetDefaults() {
+	if e.Port == 0 {
+		e.Port = 587
+	}
+	if e.Subject == "" {
+		e.Subject = "findtarget digest"
+	}
+	if e.Batch == "" {
+		e.Batch = "run"
+	}
+}
@@ -0,0 +1,6 @@
+package types
+
+// OpenBugBountyConfig struct
+type OpenBugBountyConfig struct {
+	PlatformConfig `yaml:",inline"`
+}
@@ -1,13 +1,53 @@
 package types
 
+import "time"
+
 // Config struct with nested FindTarget structure.
 type Config struct {
 	FindTarget struct {
-		BugCrowd  *BugCrowdConfig  `yaml:"bugcrowd"`
-		HackerOne *HackerOneConfig `yaml:"hackerone"`
+		BugCrowd      *BugCrowdConfig      `yaml:"bugcrowd"`
+		HackerOne     *HackerOneConfig     `yaml:"hackerone"`
+		YesWeHack     *YesWeHackConfig     `yaml:"yeswehack"`
+		Intigriti     *IntigritiConfig     `yaml:"intigriti"`
+		Immunefi      *ImmunefiConfig      `yaml:"immunefi"`
+		OpenBugBounty *OpenBugBountyConfig `yaml:"openbugbounty"`
+		File          *FileConfig          `yaml:"file"`
+		Custom        *CustomConfig        `yaml:"custom"`
 	} `yaml:"findtarget"`
-	Proxy    string `yaml:"proxy"`
-	Template string // No yaml tag needed for command line flags
+	Proxy           ProxyList         `yaml:"proxy"`
+	Timeout         time.Duration     `yaml:"timeout"`  // Timeout of every platform request
+	Headers         map[string]string `yaml:"headers"`  // Headers sent with every platform request
+	Retries         int               `yaml:"retries"`  // Retries of a failed request, -1 to disable
+	CacheDir        string            `yaml:"cacheDir"` // Directory caching the platform responses
+	CacheTTL        time.Duration     `yaml:"cacheTTL"` // Age under which cached responses are used as is
+	OutputTemplates map[string]string `yaml:"outputTemplates"`
+	Notify          *NotifyConfig     `yaml:"notify"`
+	Schedule        string            `yaml:"schedule"` // Cron expression used by the daemon
+	Jitter          time.Duration     `yaml:"jitter"`   // Random delay added to every scheduled run
+}
+
+// Options holds the command line flags.
+type Options struct {
+	Template  string
+	Templates []string // Templates run by the daemon
+	Silent    bool
+	Output    string
+	Format    string
+
+	OutputTemplate string
+	OutputDir      string
+
+	Watch   time.Duration
+	State   string
+	NoState bool
+	Removed bool
+	Proxy   []string // Overrides the proxy key of the template
+
+	Concurrency int           // Requests in flight across the platforms
+	Timeout     time.Duration // Deadline of a run, zero for none
+
+	CacheDir string        // Overrides the cacheDir key of the template
+	CacheTTL time.Duration // Overrides the cacheTTL key of the template
 }
 
 // SetDefaults assigns default values for the entire Config struct.
@@ -15,4 +55,31 @@ func (c *Config) SetDefaults() {
 	if c.FindTarget.BugCrowd != nil {
 		c.FindTarget.BugCrowd.SetDefaults()
 	}
+	if c.FindTarget.HackerOne != nil {
+		c.FindTarget.HackerOne.SetDefaults()
+	}
+	if c.FindTarget.YesWeHack != nil {
+		c.FindTarget.YesWeHack.SetDefaults()
+	}
+	if c.FindTarget.Intigriti != nil {
+		c.FindTarget.Intigriti.SetDefaults()
+	}
+	if c.FindTarget.Immunefi != nil {
+		c.FindTarget.Immunefi.SetDefaults()
+	}
+	if c.FindTarget.OpenBugBounty != nil {
+		c.FindTarget.OpenBugBounty.SetDefaults()
+	}
+	if c.FindTarget.File != nil {
+		c.FindTarget.File.SetDefaults()
+	}
+	if c.FindTarget.Custom != nil {
+		c.FindTarget.Custom.SetDefaults()
+	}
+	if c.Notify != nil && c.Notify.Telegram != nil {
+		c.Notify.Telegram.SetDefaults()
+	}
+	if c.Notify != nil && c.Notify.Email != nil {
+		c.Notify.Email.SetDefaults()
+	}
 }
@@ -0,0 +1,19 @@
+package types
+
+// PlatformConfig holds the template knobs shared by every platform.
+type PlatformConfig struct {
+	Reward      string   `yaml:"reward"`
+	Category    string   `yaml:"category"`
+	Scope       string   `yaml:"scope"`
+	MaxPrograms int      `yaml:"maxPrograms"`
+	Include     []string `yaml:"include"`
+	Kinds       []string `yaml:"kinds"`     // Asset kinds to select instead of the scope mode
+	RateLimit   float64  `yaml:"rateLimit"` // Maximum requests per second to the platform
+}
+
+// SetDefaults assigns default values for the shared platform settings.
+func (p *PlatformConfig) SetDefaults() {
+	if p.Scope == "" {
+		p.Scope = "all"
+	}
+}
@@ -0,0 +1,61 @@
+package types
+
+import "strings"
+
+// AssetKind classifies an asset independently of the platform it came from.
+type AssetKind string
+
+const (
+	AssetWildcard      AssetKind = "wildcard"
+	AssetURL           AssetKind = "url"
+	AssetIP            AssetKind = "ip"
+	AssetMobile        AssetKind = "mobile"
+	AssetSourceCode    AssetKind = "source-code"
+	AssetExecutable    AssetKind = "executable"
+	AssetHardware      AssetKind = "hardware"
+	AssetSmartContract AssetKind = "smart-contract"
+	AssetOther         AssetKind = "other"
+)
+
+// Program is a bug bounty program as reported by any platform.
+type Program struct {
+	Platform string  `json:"platform"`
+	ID       string  `json:"id,omitempty"` // Platform specific ID, when it differs from the handle
+	Handle   string  `json:"handle"`
+	Name     string  `json:"name"`
+	URL      string  `json:"url"`
+	Assets   []Asset `json:"assets"`
+}
+
+// Asset is a single in-scope or out-of-scope entry of a program.
+type Asset struct {
+	Kind           AssetKind `json:"kind"`
+	Type           string    `json:"type"`     // Asset type as named by the platform
+	Category       string    `json:"category"` // Bugcrowd style category used by the category filter
+	Identifier     string    `json:"identifier"`
+	InScope        bool      `json:"in_scope"`
+	BountyEligible bool      `json:"bounty_eligible"`
+	MaxSeverity    string    `json:"max_severity,omitempty"`
+	Instruction    string    `json:"instruction,omitempty"`
+	Removed        bool      `json:"removed,omitempty"` // Set on the assets watch mode reports as removed
+}
+
+// Target returns the value a scanner should be pointed at. Wildcards are
+// returned without their leading "*." label.
+func (a Asset) Target() string {
+	if a.Kind == AssetWildcard {
+		return strings.TrimPrefix(a.Identifier, "*.")
+	}
+	return a.Identifier
+}
+
+// InScopeAssets returns the in-scope assets of the program.
+func (p Program) InScopeAssets() []Asset {
+	var assets []Asset
+	for _, asset := range p.Assets {
+		if asset.InScope {
+			assets = append(assets, asset)
+		}
+	}
+	return assets
+}
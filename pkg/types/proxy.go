@@ -0,0 +1,29 @@
+package types
+
+import "gopkg.in/yaml.v3"
+
+// ProxyList is the proxy key of a template: a single proxy URL or a list of
+// proxies rotated per request.
+type ProxyList []string
+
+// UnmarshalYAML accepts either a string or a sequence of strings.
+func (p *ProxyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var proxy string
+		if err := node.Decode(&proxy); err != nil {
+			return err
+		}
+		*p = nil
+		if proxy != "" {
+			*p = ProxyList{proxy}
+		}
+		return nil
+	}
+
+	var proxies []string
+	if err := node.Decode(&proxies); err != nil {
+		return err
+	}
+	*p = proxies
+	return nil
+}
@@ -0,0 +1,35 @@
+package types
+
+// YesWeHackConfig struct
+type YesWeHackConfig struct {
+	PlatformConfig `yaml:",inline"`
+}
+
+// YesWeHackResponse is a page of the public program listing.
+type YesWeHackResponse struct {
+	Items      []YesWeHackProgram `json:"items"`
+	Pagination struct {
+		Page     int `json:"page"`
+		NbPages  int `json:"nb_pages"`
+		NbResult int `json:"nb_results"`
+	} `json:"pagination"`
+}
+
+// YesWeHackProgram describes a program in the listing and in the program details.
+type YesWeHackProgram struct {
+	Title           string           `json:"title"`
+	Slug            string           `json:"slug"`
+	Public          bool             `json:"public"`
+	Disabled        bool             `json:"disabled"`
+	Bounty          bool             `json:"bounty"`
+	VDP             bool             `json:"vdp"`
+	BountyRewardMin int              `json:"bounty_reward_min"`
+	BountyRewardMax int              `json:"bounty_reward_max"`
+	Scopes          []YesWeHackScope `json:"scopes"`
+	OutOfScope      []string         `json:"out_of_scope"`
+}
+
+type YesWeHackScope struct {
+	Scope     string `json:"scope"`
+	ScopeType string `json:"scope_type"`
+}